@@ -72,6 +72,7 @@ const (
 	TooManyRequests              = http.StatusTooManyRequests              // 429
 	RequestHeaderFieldsTooLarge  = http.StatusRequestHeaderFieldsTooLarge  // 431
 	UnavailableForLegalReasons   = http.StatusUnavailableForLegalReasons   // 451
+	ClientClosedRequest          = 499                                     // nginx: client went away before the response
 
 	// -----------------------------
 	// 5xx Server Error
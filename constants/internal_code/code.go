@@ -20,6 +20,7 @@ const (
 	TimeoutError       = 1015
 	UnauthorizedAction = 1016
 	ForbiddenAccess    = 1017
+	RequestCanceled    = 1018
 )
 
 // Database Layer
@@ -29,6 +30,11 @@ const (
 	DBTransactionError = 1103
 	DBRecordNotFound   = 1104
 	DBDuplicateKey     = 1105
+	DBForeignKey       = 1106
+	DBCheckViolation   = 1107
+	DBTxConflict       = 1108
+	DBTimeout          = 1109
+	DBVersionConflict  = 1110
 )
 
 // Repository / Cache
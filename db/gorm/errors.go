@@ -0,0 +1,164 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	stdErrors "errors"
+	"regexp"
+	"strings"
+
+	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
+	"gorm.io/gorm"
+)
+
+// =======================================================
+// DATABASE ERROR TRANSLATION
+// =======================================================
+
+// Catalog keys returned by ErrorKey, see errors/catalog.json.
+const (
+	KeyRecordNotFound      = "recordNotFound"
+	KeyDuplicateKey        = "duplicateKey"
+	KeyForeignKeyViolation = "foreignKeyViolation"
+	KeyCheckViolation      = "checkViolation"
+	KeyTransactionError    = "transactionError"
+	KeyTransactionConflict = "transactionConflict"
+	KeyTimeout             = "dbTimeout"
+	KeyCanceled            = "requestCanceled" // the caller went away, not a DB failure
+	KeyConnectionError     = "dbConnectionError"
+	KeyVersionConflict     = "versionConflict" // optimistic locking, raised by repositories
+)
+
+// sqlStater is implemented by pgx (*pgconn.PgError) and other drivers exposing SQLSTATE.
+type sqlStater interface {
+	SQLState() string
+}
+
+// postgres SQLSTATE codes
+var sqlStateKeys = map[string]string{
+	"23505": KeyDuplicateKey,
+	"23503": KeyForeignKeyViolation,
+	"23514": KeyCheckViolation,
+	"23502": KeyCheckViolation, // not_null_violation
+	"40001": KeyTransactionConflict,
+	"40P01": KeyTransactionConflict,
+	"55P03": KeyTransactionConflict, // lock_not_available (NOWAIT)
+	"57014": KeyTimeout,
+	"25P02": KeyTransactionError, // in_failed_sql_transaction
+}
+
+// mysql error numbers, the driver formats them as "Error 1062 (23000): ..."
+var mysqlCodeKeys = map[string]string{
+	"1062": KeyDuplicateKey,
+	"1451": KeyForeignKeyViolation,
+	"1452": KeyForeignKeyViolation,
+	"3819": KeyCheckViolation,
+	"1048": KeyCheckViolation, // column cannot be null
+	"1213": KeyTransactionConflict,
+	"1205": KeyTimeout, // lock wait timeout
+	"2006": KeyConnectionError,
+	"2013": KeyConnectionError,
+}
+
+var mysqlCodePattern = regexp.MustCompile(`^Error (\d{4})`)
+
+// message fragments for drivers without structured codes (lib/pq, sqlite)
+var messageKeys = []struct {
+	fragment string
+	key      string
+}{
+	{"duplicate key value", KeyDuplicateKey},
+	{"unique constraint failed", KeyDuplicateKey},
+	{"violates foreign key constraint", KeyForeignKeyViolation},
+	{"foreign key constraint failed", KeyForeignKeyViolation},
+	{"violates check constraint", KeyCheckViolation},
+	{"check constraint failed", KeyCheckViolation},
+	{"violates not-null constraint", KeyCheckViolation},
+	{"not null constraint failed", KeyCheckViolation},
+	{"deadlock detected", KeyTransactionConflict},
+	{"could not serialize access", KeyTransactionConflict},
+	{"database is locked", KeyTransactionConflict},
+	{"canceling statement due to statement timeout", KeyTimeout},
+	{"connection refused", KeyConnectionError},
+	{"bad connection", KeyConnectionError},
+}
+
+// ErrorKey classifies a gorm / driver error into a catalog key, or returns ""
+// when err is nil or not recognised. Gorm's own sentinels are only returned by
+// dialectors when gorm.Config.TranslateError is enabled, so raw driver codes and
+// messages are checked as well.
+func ErrorKey(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	switch {
+	case stdErrors.Is(err, gorm.ErrRecordNotFound), stdErrors.Is(err, sql.ErrNoRows):
+		return KeyRecordNotFound
+	case stdErrors.Is(err, gorm.ErrDuplicatedKey):
+		return KeyDuplicateKey
+	case stdErrors.Is(err, gorm.ErrForeignKeyViolated):
+		return KeyForeignKeyViolation
+	case stdErrors.Is(err, gorm.ErrCheckConstraintViolated):
+		return KeyCheckViolation
+	case stdErrors.Is(err, gorm.ErrInvalidTransaction), stdErrors.Is(err, sql.ErrTxDone):
+		return KeyTransactionError
+	case stdErrors.Is(err, context.Canceled):
+		return KeyCanceled
+	case stdErrors.Is(err, context.DeadlineExceeded):
+		return KeyTimeout
+	case stdErrors.Is(err, driver.ErrBadConn), stdErrors.Is(err, sql.ErrConnDone):
+		return KeyConnectionError
+	}
+
+	var stater sqlStater
+	if stdErrors.As(err, &stater) {
+		state := stater.SQLState()
+		if key, ok := sqlStateKeys[state]; ok {
+			return key
+		}
+		if strings.HasPrefix(state, "08") {
+			return KeyConnectionError
+		}
+	}
+
+	msg := err.Error()
+	if m := mysqlCodePattern.FindStringSubmatch(msg); m != nil {
+		if key, ok := mysqlCodeKeys[m[1]]; ok {
+			return key
+		}
+	}
+
+	msg = strings.ToLower(msg)
+	for _, mk := range messageKeys {
+		if strings.Contains(msg, mk.fragment) {
+			return mk.key
+		}
+	}
+	return ""
+}
+
+// TranslateError logs and converts a gorm / driver error into the matching
+// AppError (DB* internal codes). Unrecognised errors use fallbackKey, e.g.
+// "errorCreateResource". detail ends up in the log message only.
+// Returns nil for nil and AppErrors unchanged, so it is safe to call on any
+// error coming out of a repository.
+//
+//	if err := db.Create(&user).Error; err != nil {
+//		return dbGorm.TranslateError(ctx, err, "errorCreateResource", "users")
+//	}
+func TranslateError(ctx context.Context, err error, fallbackKey, detail string) error {
+	if err == nil {
+		return nil
+	}
+	if appErr, ok := appError.FromError(err); ok {
+		return appErr
+	}
+
+	key := ErrorKey(err)
+	if key == "" {
+		key = fallbackKey
+	}
+	return appError.NewCtx(ctx, key, detail, err)
+}
@@ -0,0 +1,38 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
+	"google.golang.org/grpc/codes"
+)
+
+func TestErrorKeyContext(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{context.Canceled, KeyCanceled},
+		{fmt.Errorf("query: %w", context.Canceled), KeyCanceled},
+		{context.DeadlineExceeded, KeyTimeout},
+		{fmt.Errorf("query: %w", context.DeadlineExceeded), KeyTimeout},
+	}
+	for _, tt := range tests {
+		if got := ErrorKey(tt.err); got != tt.want {
+			t.Errorf("ErrorKey(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestTranslateErrorCanceled(t *testing.T) {
+	err := TranslateError(context.Background(), context.Canceled, "errorFindResource", "users")
+	appErr, ok := appError.FromError(err)
+	if !ok {
+		t.Fatalf("TranslateError returned %T, want AppError", err)
+	}
+	if appErr.HttpCode != 499 || appErr.GrpcCode != codes.Canceled {
+		t.Fatalf("canceled = HTTP %d / %s, want 499 / Canceled", appErr.HttpCode, appErr.GrpcCode)
+	}
+}
@@ -1,70 +1,209 @@
 package repository
 
 import (
-	"fmt"
+	"context"
 
-	"github.com/ginanjar-template-golang/shared-pkg/errors"
+	dbGorm "github.com/ginanjar-template-golang/shared-pkg/db/gorm"
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
+// IBaseRepository is the original uint keyed CRUD API, kept as is so existing
+// implementations and mocks still satisfy it.
 type IBaseRepository[T any] interface {
+	ICrudRepository[T, uint]
+}
+
+// ICrudRepository is IBaseRepository for any key type.
+type ICrudRepository[T any, ID comparable] interface {
 	FindAll() ([]*T, error)
-	FindByID(id uint) (*T, error)
+	FindByID(id ID) (*T, error)
 	Create(entity *T) (*T, error)
 	Update(entity *T) (*T, error)
-	Delete(id uint) error
+	Delete(id ID) error
+}
+
+// IContextRepository is the CRUD API bound to a request context.
+type IContextRepository[T any, ID comparable] interface {
+	FindAllCtx(ctx context.Context, opts ...Option) ([]*T, error)
+	FindByIDCtx(ctx context.Context, id ID, opts ...Option) (*T, error)
+	CreateCtx(ctx context.Context, entity *T, opts ...Option) (*T, error)
+	UpdateCtx(ctx context.Context, entity *T, opts ...Option) (*T, error)
+	UpdateFieldsCtx(ctx context.Context, entity *T, fields map[string]any, opts ...Option) (*T, error)
+	DeleteCtx(ctx context.Context, id ID, opts ...Option) error
+}
+
+// ISoftDeleteRepository restores and purges soft deleted rows.
+type ISoftDeleteRepository[T any, ID comparable] interface {
+	SoftDeleteCtx(ctx context.Context, id ID, opts ...Option) error
+	RestoreCtx(ctx context.Context, id ID, opts ...Option) error
+	ForceDeleteCtx(ctx context.Context, id ID, opts ...Option) error
+	FindDeletedCtx(ctx context.Context, opts ...Option) ([]*T, error)
+}
+
+// IBulkRepository writes many rows per call.
+type IBulkRepository[T any, ID comparable] interface {
+	CreateManyCtx(ctx context.Context, entities []*T, batchSize int, opts ...Option) (int64, error)
+	UpsertCtx(ctx context.Context, entities []*T, conflictColumns, updateColumns []string, opts ...Option) (int64, error)
+	UpdateWhereCtx(ctx context.Context, fields map[string]any, spec Spec, opts ...Option) (int64, error)
+	DeleteManyCtx(ctx context.Context, ids []ID, opts ...Option) (int64, error)
+}
+
+// ISpecRepository queries by Spec.
+type ISpecRepository[T any] interface {
+	FindOneCtx(ctx context.Context, spec Spec, opts ...Option) (*T, error)
+	FindManyCtx(ctx context.Context, spec Spec, opts ...Option) ([]*T, error)
+	CountCtx(ctx context.Context, spec Spec, opts ...Option) (int64, error)
+	ExistsCtx(ctx context.Context, spec Spec, opts ...Option) (bool, error)
+}
+
+// IPageRepository returns offset and keyset pages.
+type IPageRepository[T any] interface {
+	FindPageCtx(ctx context.Context, q PageQuery, opts ...Option) (*PageResult[T], error)
+	FindCursorCtx(ctx context.Context, q CursorQuery, opts ...Option) (*CursorResult[T], error)
 }
 
-type BaseRepository[T any] struct {
+// IRepository is implemented by Repository for entities T keyed by ID. Depend
+// on the smaller interfaces where a consumer needs only part of it.
+type IRepository[T any, ID comparable] interface {
+	ICrudRepository[T, ID]
+	IContextRepository[T, ID]
+	ISoftDeleteRepository[T, ID]
+	IBulkRepository[T, ID]
+	ISpecRepository[T]
+	IPageRepository[T]
+}
+
+// Repository is the generic gorm repository for entities T keyed by ID:
+// uint / int64, string, uuid.UUID, or a struct for composite keys whose
+// fields are named like T's primary key fields.
+//
+//	type OrderItemKey struct {
+//		OrderID   uuid.UUID
+//		ProductID int64
+//	}
+//	itemRepo := repository.NewRepository[OrderItem, OrderItemKey](db, "order_items")
+//	item, err := itemRepo.FindByIDCtx(ctx, OrderItemKey{OrderID: orderID, ProductID: 7})
+type Repository[T any, ID comparable] struct {
 	DB        *gorm.DB
 	TableName string
+
+	// SoftDeleteColumn switches soft delete to a plain column, nil uses T's gorm.DeletedAt
+	SoftDeleteColumn *SoftDeleteColumn
+	// TenantColumn scopes every call to the tenant in ctx, "" disables (see DefaultTenantColumn)
+	TenantColumn string
+}
+
+// BaseRepository keeps the uint keyed API.
+type BaseRepository[T any] = Repository[T, uint]
+
+func NewRepository[T any, ID comparable](db *gorm.DB, tableName string) *Repository[T, ID] {
+	return &Repository[T, ID]{DB: db, TableName: tableName}
 }
 
 func NewBaseRepository[T any](db *gorm.DB, tableName string) *BaseRepository[T] {
-	return &BaseRepository[T]{DB: db, TableName: tableName}
+	return NewRepository[T, uint](db, tableName)
 }
 
-func (r *BaseRepository[T]) FindAll() ([]*T, error) {
-	var results []*T
-	err := r.DB.Find(&results).Error
-	if err != nil {
-		return nil, errors.GeneralError(fmt.Sprintf("FindAll %s", r.TableName), err)
+// conn returns the transaction stored in ctx (see dbGorm.WithTx) or r.DB, bound to ctx.
+func (r *Repository[T, ID]) conn(ctx context.Context) *gorm.DB {
+	return dbGorm.Conn(ctx, r.DB)
+}
+
+// scoped returns the connection restricted to the rows o may see: soft
+// deleted rows are hidden unless WithTrashed / WithUnscoped, FindDeletedCtx
+// sees only those, and tenant scoped models only see the tenant in ctx.
+// WithTrashed / FindDeletedCtx on a model that cannot be soft deleted fail
+// with invalidOption instead of quietly returning live rows or nothing.
+func (r *Repository[T, ID]) scoped(ctx context.Context, o *queryOptions) *gorm.DB {
+	db := r.conn(ctx)
+	switch {
+	case o.unscoped:
+		db = db.Unscoped()
+	case o.trashed != trashedExclude && r.trashedExpr(true) == nil:
+		_ = db.AddError(r.noSoftDelete(ctx))
+		return db
+	case o.trashed == trashedInclude:
+		db = db.Unscoped()
+	case o.trashed == trashedOnly:
+		db = db.Unscoped().Where(r.trashedExpr(true))
+	case r.SoftDeleteColumn != nil:
+		db = db.Where(r.trashedExpr(false))
 	}
-	return results, nil
+	return r.tenantScope(ctx, o, db)
 }
 
-func (r *BaseRepository[T]) FindByID(id uint) (*T, error) {
-	var result T
-	err := r.DB.First(&result, id).Error
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, errors.ResourceNotFound(fmt.Sprintf("%s id=%d", r.TableName, id), nil)
-		}
-		return nil, errors.GeneralError(fmt.Sprintf("FindByID %s", r.TableName), err)
+// schema returns the parsed gorm schema of T, cached by gorm.
+func (r *Repository[T, ID]) schema() (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: r.DB}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, err
 	}
-	return &result, nil
+	return stmt.Schema, nil
+}
+
+// The context-less methods run on context.Background(): on tenant scoped
+// repositories they return forbidden, use the Ctx variants.
+
+func (r *Repository[T, ID]) FindAll() ([]*T, error) {
+	return r.FindAllCtx(context.Background())
+}
+
+func (r *Repository[T, ID]) FindByID(id ID) (*T, error) {
+	return r.FindByIDCtx(context.Background(), id)
+}
+
+func (r *Repository[T, ID]) Create(entity *T) (*T, error) {
+	return r.CreateCtx(context.Background(), entity)
+}
+
+func (r *Repository[T, ID]) Update(entity *T) (*T, error) {
+	return r.UpdateCtx(context.Background(), entity)
+}
+
+func (r *Repository[T, ID]) Delete(id ID) error {
+	return r.DeleteCtx(context.Background(), id)
 }
 
-func (r *BaseRepository[T]) Create(entity *T) (*T, error) {
-	err := r.DB.Create(entity).Error
+// =======================================================
+// CONTEXT-AWARE VARIANTS
+// =======================================================
+
+func (r *Repository[T, ID]) FindAllCtx(ctx context.Context, opts ...Option) ([]*T, error) {
+	var results []*T
+	o := newQueryOptions(opts)
+	db := o.apply(r.scoped(ctx, o), true)
+	err := db.Find(&results).Error
 	if err != nil {
-		return nil, errors.CreateResourceError(r.TableName, err)
+		return nil, dbGorm.TranslateError(ctx, err, "errorFindResource", r.TableName)
 	}
-	return entity, nil
+	return results, nil
 }
 
-func (r *BaseRepository[T]) Update(entity *T) (*T, error) {
-	err := r.DB.Save(entity).Error
+func (r *Repository[T, ID]) FindByIDCtx(ctx context.Context, id ID, opts ...Option) (*T, error) {
+	var result T
+	cond, err := r.keyExpr(id)
 	if err != nil {
-		return nil, errors.UpdateResourceError(r.TableName, err)
+		return nil, dbGorm.TranslateError(ctx, err, "errorFindResource", r.idDetail(id))
 	}
-	return entity, nil
+	o := newQueryOptions(opts)
+	db := o.apply(r.scoped(ctx, o), true)
+	err = db.Where(cond).First(&result).Error
+	if err != nil {
+		return nil, dbGorm.TranslateError(ctx, err, "errorFindResource", r.idDetail(id))
+	}
+	return &result, nil
 }
 
-func (r *BaseRepository[T]) Delete(id uint) error {
-	err := r.DB.Delete(new(T), id).Error
+func (r *Repository[T, ID]) CreateCtx(ctx context.Context, entity *T, opts ...Option) (*T, error) {
+	o := newQueryOptions(opts)
+	if err := r.stampTenant(ctx, o, entity); err != nil {
+		return nil, err
+	}
+	db := o.apply(r.conn(ctx), false)
+	err := db.Create(entity).Error
 	if err != nil {
-		return errors.DeleteResourceError(fmt.Sprintf("%s id=%d", r.TableName, id), err)
+		return nil, dbGorm.TranslateError(ctx, err, "errorCreateResource", r.TableName)
 	}
-	return nil
+	return entity, nil
 }
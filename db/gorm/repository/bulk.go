@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	dbGorm "github.com/ginanjar-template-golang/shared-pkg/db/gorm"
+	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// =======================================================
+// BULK OPERATIONS
+// =======================================================
+
+// DefaultBatchSize is used by CreateManyCtx (batchSize <= 0) and UpsertCtx.
+const DefaultBatchSize = 100
+
+// BulkFailure is the Data of the AppError returned when a bulk write stops
+// part way. Every batch is its own statement, so rows written before the
+// failure stay unless ctx carries a transaction (see dbGorm.RunInTx).
+type BulkFailure struct {
+	Affected int64 `json:"affected"`
+	Total    int   `json:"total"`
+	FailedAt int   `json:"failed_at,omitempty"` // index of the first entity of the failed batch
+}
+
+// CreateManyCtx inserts entities batchSize rows per statement and returns the
+// number of rows inserted. Primary keys are filled in like CreateCtx.
+//
+//	n, err := productRepo.CreateManyCtx(ctx, products, 500)
+func (r *Repository[T, ID]) CreateManyCtx(ctx context.Context, entities []*T, batchSize int, opts ...Option) (int64, error) {
+	o := newQueryOptions(opts)
+	if err := r.stampTenant(ctx, o, entities...); err != nil {
+		return 0, err
+	}
+	return r.createBatches(ctx, o.apply(r.conn(ctx), false), entities, batchSize)
+}
+
+// UpsertCtx inserts entities, updating updateColumns of the rows that collide on
+// conflictColumns (all columns when updateColumns is empty). The returned count
+// is the driver's: MySQL reports 2 for every updated row. For tenant scoped
+// models rows of other tenants are left alone (ON CONFLICT ... WHERE), MySQL
+// lacks that clause so include the tenant column in the unique key there.
+//
+//	n, err := stockRepo.UpsertCtx(ctx, stocks, []string{"sku", "warehouse_id"}, []string{"qty", "updated_at"})
+func (r *Repository[T, ID]) UpsertCtx(ctx context.Context, entities []*T, conflictColumns, updateColumns []string, opts ...Option) (int64, error) {
+	onConflict := clause.OnConflict{}
+	for _, column := range conflictColumns {
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: column})
+	}
+	if len(updateColumns) > 0 {
+		onConflict.DoUpdates = clause.AssignmentColumns(updateColumns)
+	} else {
+		onConflict.UpdateAll = true
+	}
+
+	o := newQueryOptions(opts)
+	if err := r.stampTenant(ctx, o, entities...); err != nil {
+		return 0, err
+	}
+	if field, value, _ := r.tenant(ctx, o); field != nil {
+		onConflict.Where = clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: value},
+		}}
+	}
+	return r.createBatches(ctx, o.apply(r.conn(ctx), false).Clauses(onConflict), entities, DefaultBatchSize)
+}
+
+// createBatches runs db.Create per batch and reports a BulkFailure once some
+// rows have been written.
+func (r *Repository[T, ID]) createBatches(ctx context.Context, db *gorm.DB, entities []*T, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	var affected int64
+	for start := 0; start < len(entities); start += batchSize {
+		end := min(start+batchSize, len(entities))
+		result := db.Session(&gorm.Session{}).Create(entities[start:end])
+		if result.Error != nil {
+			return affected, r.bulkError(ctx, result.Error, "errorCreateResource", BulkFailure{Affected: affected, Total: len(entities), FailedAt: start})
+		}
+		affected += result.RowsAffected
+	}
+	return affected, nil
+}
+
+// UpdateWhereCtx sets fields on every live row matching spec and returns the
+// number of rows updated. The zero Spec is refused so a missing condition never
+// updates the whole table. Hooks and optimistic locking are not applied.
+//
+//	spec := repository.Where("status = ? AND expires_at < ?", "pending", time.Now())
+//	n, err := orderRepo.UpdateWhereCtx(ctx, map[string]any{"status": "expired"}, spec)
+func (r *Repository[T, ID]) UpdateWhereCtx(ctx context.Context, fields map[string]any, spec Spec, opts ...Option) (int64, error) {
+	if spec.cond == nil {
+		return 0, appError.InvalidOptionErrorCtx(ctx, r.TableName, "UpdateWhereCtx requires a condition")
+	}
+	if len(fields) == 0 {
+		return 0, nil
+	}
+	o := newQueryOptions(opts)
+	if err := r.checkTenantFields(ctx, o, fields); err != nil {
+		return 0, err
+	}
+
+	result := spec.scope(r.scoped(ctx, o).Model(new(T))).Updates(fields)
+	if result.Error != nil {
+		return 0, dbGorm.TranslateError(ctx, result.Error, "errorUpdateResource", r.TableName)
+	}
+	return result.RowsAffected, nil
+}
+
+// DeleteManyCtx deletes the rows ids like DeleteCtx (soft when T supports it) and
+// returns the number deleted. When some ids matched no live row the count is
+// returned together with a recordNotFound AppError carrying a BulkFailure.
+func (r *Repository[T, ID]) DeleteManyCtx(ctx context.Context, ids []ID, opts ...Option) (int64, error) {
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	cond, err := r.keysExpr(ids)
+	if err != nil {
+		return 0, dbGorm.TranslateError(ctx, err, "errorDeleteResource", r.TableName)
+	}
+
+	o := newQueryOptions(opts)
+	var result *gorm.DB
+	if !o.unscoped {
+		result = r.softDelete(ctx, o, cond)
+	}
+	if result == nil {
+		result = r.scoped(ctx, &queryOptions{unscoped: true, allTenants: o.allTenants}).Where(cond).Delete(new(T))
+	}
+
+	if result.Error != nil {
+		return 0, dbGorm.TranslateError(ctx, result.Error, "errorDeleteResource", r.TableName)
+	}
+	if result.RowsAffected < int64(len(ids)) {
+		detail := fmt.Sprintf("%s: %d of %d ids deleted", r.TableName, result.RowsAffected, len(ids))
+		appErr := appError.NewCtx(ctx, dbGorm.KeyRecordNotFound, detail, nil)
+		appErr.Data = BulkFailure{Affected: result.RowsAffected, Total: len(ids)}
+		return result.RowsAffected, appErr
+	}
+	return result.RowsAffected, nil
+}
+
+// bulkError translates err; when rows were already written the AppError
+// carries failure as Data.
+func (r *Repository[T, ID]) bulkError(ctx context.Context, err error, fallbackKey string, failure BulkFailure) error {
+	if failure.Affected == 0 {
+		return dbGorm.TranslateError(ctx, err, fallbackKey, r.TableName)
+	}
+
+	key := dbGorm.ErrorKey(err)
+	if key == "" {
+		key = fallbackKey
+	}
+	detail := fmt.Sprintf("%s: batch at %d failed, %d of %d rows written", r.TableName, failure.FailedAt, failure.Affected, failure.Total)
+	appErr := appError.NewCtx(ctx, key, detail, err)
+	appErr.Data = failure
+	return appErr
+}
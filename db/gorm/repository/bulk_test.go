@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	dbGorm "github.com/ginanjar-template-golang/shared-pkg/db/gorm"
+	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
+	"github.com/ginanjar-template-golang/shared-pkg/utils"
+)
+
+type bulkStock struct {
+	ID       uint
+	TenantID string
+	SKU      string `gorm:"uniqueIndex"`
+	Qty      int
+}
+
+func newBulkStocks(t *testing.T) *BaseRepository[bulkStock] {
+	t.Helper()
+	return NewBaseRepository[bulkStock](openTestDB(t, &bulkStock{}), "bulk_stocks")
+}
+
+func stockQty(t *testing.T, ctx context.Context, repo *BaseRepository[bulkStock]) map[string]int {
+	t.Helper()
+	rows, err := repo.FindAllCtx(ctx, WithAllTenants())
+	if err != nil {
+		t.Fatalf("find all: %v", err)
+	}
+	out := make(map[string]int, len(rows))
+	for _, row := range rows {
+		out[row.SKU] = row.Qty
+	}
+	return out
+}
+
+func TestCreateManyCtx(t *testing.T) {
+	ctx := context.Background()
+	repo := newBulkStocks(t)
+
+	stocks := []*bulkStock{{SKU: "a"}, {SKU: "b"}, {SKU: "c"}, {SKU: "d"}, {SKU: "e"}}
+	n, err := repo.CreateManyCtx(ctx, stocks, 2)
+	if err != nil || n != 5 {
+		t.Fatalf("CreateManyCtx = %d, %v, want 5 rows", n, err)
+	}
+	for _, s := range stocks {
+		if s.ID == 0 {
+			t.Fatalf("%s has no primary key after CreateManyCtx", s.SKU)
+		}
+	}
+
+	if n, err := repo.CreateManyCtx(ctx, nil, 2); err != nil || n != 0 {
+		t.Fatalf("CreateManyCtx(nil) = %d, %v, want 0, nil", n, err)
+	}
+
+	// the second batch collides with "a": the first one stays and is reported
+	n, err = repo.CreateManyCtx(ctx, []*bulkStock{{SKU: "f"}, {SKU: "g"}, {SKU: "a"}}, 2)
+	if n != 2 {
+		t.Fatalf("rows written before the failure = %d, want 2", n)
+	}
+	assertErrorKey(t, err, dbGorm.KeyDuplicateKey)
+	appErr, _ := appError.FromError(err)
+	if failure, ok := appErr.Data.(BulkFailure); !ok || failure != (BulkFailure{Affected: 2, Total: 3, FailedAt: 2}) {
+		t.Fatalf("data = %#v, want BulkFailure{2, 3, 2}", appErr.Data)
+	}
+}
+
+func TestUpsertCtx(t *testing.T) {
+	ctx := context.Background()
+	repo := newBulkStocks(t)
+
+	if _, err := repo.CreateManyCtx(ctx, []*bulkStock{{SKU: "a", Qty: 1}, {SKU: "b", Qty: 1}}, 0); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	_, err := repo.UpsertCtx(ctx, []*bulkStock{{SKU: "a", Qty: 5}, {SKU: "c", Qty: 7}}, []string{"sku"}, []string{"qty"})
+	if err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	want := map[string]int{"a": 5, "b": 1, "c": 7}
+	if got := stockQty(t, ctx, repo); len(got) != 3 || got["a"] != 5 || got["b"] != 1 || got["c"] != 7 {
+		t.Fatalf("stock = %v, want %v", got, want)
+	}
+}
+
+func TestUpsertCtxTenantConflict(t *testing.T) {
+	repo := newBulkStocks(t)
+	repo.TenantColumn = DefaultTenantColumn
+	ctxA := utils.WithTenantID(context.Background(), "a")
+	ctxB := utils.WithTenantID(context.Background(), "b")
+
+	if _, err := repo.UpsertCtx(ctxA, []*bulkStock{{SKU: "x", Qty: 1}}, []string{"sku"}, []string{"qty"}); err != nil {
+		t.Fatalf("upsert a: %v", err)
+	}
+
+	// tenant b collides on tenant a's sku: the row of a is left alone
+	n, err := repo.UpsertCtx(ctxB, []*bulkStock{{SKU: "x", Qty: 99}}, []string{"sku"}, []string{"qty"})
+	if err != nil {
+		t.Fatalf("upsert b: %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("rows affected = %d, want 0", n)
+	}
+	rows, err := repo.FindAllCtx(ctxA)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Qty != 1 || rows[0].TenantID != "a" {
+		t.Fatalf("tenant a rows = %+v, want sku x untouched with qty 1", rows)
+	}
+
+	// the own row of a tenant is updated
+	if _, err := repo.UpsertCtx(ctxA, []*bulkStock{{SKU: "x", Qty: 3}}, []string{"sku"}, []string{"qty"}); err != nil {
+		t.Fatalf("upsert a again: %v", err)
+	}
+	if got := stockQty(t, ctxA, repo); got["x"] != 3 {
+		t.Fatalf("qty = %d, want 3", got["x"])
+	}
+
+	_, err = repo.UpsertCtx(ctxA, []*bulkStock{{TenantID: "b", SKU: "y"}}, []string{"sku"}, nil)
+	assertErrorKey(t, err, "forbidden")
+}
+
+func TestUpdateWhereCtx(t *testing.T) {
+	ctx := context.Background()
+	repo := newBulkStocks(t)
+	if _, err := repo.CreateManyCtx(ctx, []*bulkStock{{SKU: "a", Qty: 1}, {SKU: "b", Qty: 2}, {SKU: "c", Qty: 3}}, 0); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	n, err := repo.UpdateWhereCtx(ctx, map[string]any{"qty": 0}, Where("qty >= ?", 2))
+	if err != nil || n != 2 {
+		t.Fatalf("UpdateWhereCtx = %d, %v, want 2 rows", n, err)
+	}
+	if got := stockQty(t, ctx, repo); got["a"] != 1 || got["b"] != 0 || got["c"] != 0 {
+		t.Fatalf("stock = %v, want a=1 b=0 c=0", got)
+	}
+
+	// the zero Spec never updates the whole table
+	_, err = repo.UpdateWhereCtx(ctx, map[string]any{"qty": 42}, Spec{})
+	assertErrorKey(t, err, "invalidOption")
+	_, err = repo.UpdateWhereCtx(ctx, map[string]any{"qty": 42}, Spec{}.OrderBy("id", false))
+	assertErrorKey(t, err, "invalidOption")
+	if got := stockQty(t, ctx, repo); got["a"] != 1 || got["b"] != 0 || got["c"] != 0 {
+		t.Fatalf("stock after refused update = %v, want it unchanged", got)
+	}
+
+	if n, err := repo.UpdateWhereCtx(ctx, nil, Where("sku = ?", "a")); err != nil || n != 0 {
+		t.Fatalf("UpdateWhereCtx without fields = %d, %v, want 0, nil", n, err)
+	}
+}
+
+func TestUpdateWhereCtxOptions(t *testing.T) {
+	ctx := context.Background()
+	docs := NewBaseRepository[trashDoc](openTestDB(t, &trashDoc{}), "trash_docs")
+	live, _ := docs.CreateCtx(ctx, &trashDoc{Title: "live"})
+	gone, _ := docs.CreateCtx(ctx, &trashDoc{Title: "gone"})
+	if err := docs.SoftDeleteCtx(ctx, gone.ID); err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+
+	all := Where("id IN ?", []uint{live.ID, gone.ID})
+	if n, err := docs.UpdateWhereCtx(ctx, map[string]any{"title": "x"}, all); err != nil || n != 1 {
+		t.Fatalf("UpdateWhereCtx = %d, %v, want only the live row", n, err)
+	}
+	if n, err := docs.UpdateWhereCtx(ctx, map[string]any{"title": "y"}, all, WithTrashed()); err != nil || n != 2 {
+		t.Fatalf("UpdateWhereCtx(WithTrashed) = %d, %v, want 2 rows", n, err)
+	}
+
+	stocks := newBulkStocks(t)
+	stocks.TenantColumn = DefaultTenantColumn
+	ctxA := utils.WithTenantID(ctx, "a")
+	ctxB := utils.WithTenantID(ctx, "b")
+	if _, err := stocks.CreateManyCtx(ctxA, []*bulkStock{{SKU: "a1", Qty: 1}}, 0); err != nil {
+		t.Fatalf("seed a: %v", err)
+	}
+	if _, err := stocks.CreateManyCtx(ctxB, []*bulkStock{{SKU: "b1", Qty: 1}}, 0); err != nil {
+		t.Fatalf("seed b: %v", err)
+	}
+
+	everyQty := Where("qty > ?", 0)
+	if n, err := stocks.UpdateWhereCtx(ctxB, map[string]any{"qty": 5}, everyQty); err != nil || n != 1 {
+		t.Fatalf("UpdateWhereCtx tenant b = %d, %v, want only its own row", n, err)
+	}
+	if n, err := stocks.UpdateWhereCtx(ctx, map[string]any{"qty": 9}, everyQty, WithAllTenants()); err != nil || n != 2 {
+		t.Fatalf("UpdateWhereCtx(WithAllTenants) = %d, %v, want 2 rows", n, err)
+	}
+	_, err := stocks.UpdateWhereCtx(ctxA, map[string]any{"tenant_id": "b"}, everyQty)
+	assertErrorKey(t, err, "forbidden")
+}
+
+func TestDeleteManyCtx(t *testing.T) {
+	ctx := context.Background()
+	repo := newBulkStocks(t)
+	stocks := []*bulkStock{{SKU: "a"}, {SKU: "b"}, {SKU: "c"}}
+	if _, err := repo.CreateManyCtx(ctx, stocks, 0); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	// no ids deletes nothing rather than every row
+	for _, ids := range [][]uint{nil, {}} {
+		if n, err := repo.DeleteManyCtx(ctx, ids); err != nil || n != 0 {
+			t.Fatalf("DeleteManyCtx(%v) = %d, %v, want 0, nil", ids, n, err)
+		}
+	}
+	if got := stockQty(t, ctx, repo); len(got) != 3 {
+		t.Fatalf("rows after empty DeleteManyCtx = %d, want 3", len(got))
+	}
+
+	n, err := repo.DeleteManyCtx(ctx, []uint{stocks[0].ID, stocks[1].ID, stocks[0].ID, 999})
+	if n != 2 {
+		t.Fatalf("deleted = %d, want 2", n)
+	}
+	assertErrorKey(t, err, dbGorm.KeyRecordNotFound)
+	appErr, _ := appError.FromError(err)
+	if failure, ok := appErr.Data.(BulkFailure); !ok || failure.Affected != 2 || failure.Total != 3 {
+		t.Fatalf("data = %#v, want BulkFailure{Affected: 2, Total: 3}", appErr.Data)
+	}
+	if got := stockQty(t, ctx, repo); len(got) != 1 || got["c"] != 0 {
+		t.Fatalf("rows left = %v, want only c", got)
+	}
+}
+
+func TestDeleteManyCtxSoftDelete(t *testing.T) {
+	ctx := context.Background()
+	docs := NewBaseRepository[trashDoc](openTestDB(t, &trashDoc{}), "trash_docs")
+	a, _ := docs.CreateCtx(ctx, &trashDoc{Title: "a"})
+	b, _ := docs.CreateCtx(ctx, &trashDoc{Title: "b"})
+
+	if n, err := docs.DeleteManyCtx(ctx, []uint{a.ID, b.ID}); err != nil || n != 2 {
+		t.Fatalf("DeleteManyCtx = %d, %v, want 2", n, err)
+	}
+	deleted, err := docs.FindDeletedCtx(ctx)
+	if err != nil || len(deleted) != 2 {
+		t.Fatalf("FindDeletedCtx = %d rows, %v, want 2 soft deleted", len(deleted), err)
+	}
+
+	if n, err := docs.DeleteManyCtx(ctx, []uint{a.ID}, WithUnscoped()); err != nil || n != 1 {
+		t.Fatalf("DeleteManyCtx(WithUnscoped) = %d, %v, want 1", n, err)
+	}
+	all, _ := docs.FindAllCtx(ctx, WithTrashed())
+	if len(all) != 1 || all[0].ID != b.ID {
+		t.Fatalf("rows left = %+v, want only b", all)
+	}
+}
@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	dbGorm "github.com/ginanjar-template-golang/shared-pkg/db/gorm"
+	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
+	grpcResponse "github.com/ginanjar-template-golang/shared-pkg/response/grpc_response"
+	httpResponse "github.com/ginanjar-template-golang/shared-pkg/response/http_response"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// =======================================================
+// CURSOR (KEYSET) PAGINATION
+// =======================================================
+
+var errInvalidCursor = stdErrors.New("invalid cursor")
+
+var cursorSecret atomic.Pointer[[]byte]
+
+func init() {
+	secret := make([]byte, 32)
+	_, _ = rand.Read(secret)
+	cursorSecret.Store(&secret)
+}
+
+// SetCursorSecret sets the HMAC key signing cursor tokens. Call it at startup
+// with the same value on every instance, otherwise a random per-process key is
+// used and cursors stop working across instances and restarts.
+func SetCursorSecret(secret []byte) {
+	copied := append([]byte(nil), secret...)
+	cursorSecret.Store(&copied)
+}
+
+// CursorQuery describes one keyset page. Cursor is the opaque token returned
+// by the previous page ("" for the first page). Sort columns must be non-null;
+// the primary key columns are appended as tie-breaker.
+type CursorQuery struct {
+	Cursor  string
+	Limit   int
+	Sort    []SortField
+	Filters []Filter
+	Spec    Spec // server side conditions, its OrderBy is ignored
+
+	AllowedSorts   map[string]string
+	AllowedFilters map[string]string
+}
+
+// CursorResult is one keyset page. HasMore reports whether more rows exist in
+// the direction just travelled.
+type CursorResult[T any] struct {
+	Items      []*T
+	Limit      int
+	NextCursor string
+	PrevCursor string
+	HasMore    bool
+}
+
+// ToHTTP converts the page for httpResponse.CursorPaginationResponse.
+func (p *CursorResult[T]) ToHTTP() httpResponse.Pagination {
+	return httpResponse.Pagination{
+		Size:       len(p.Items),
+		Limit:      p.Limit,
+		Results:    p.Items,
+		NextCursor: p.NextCursor,
+		PrevCursor: p.PrevCursor,
+		HasMore:    p.HasMore,
+	}
+}
+
+// ToGRPC converts the page for grpcResponse.CursorPaginationSuccess.
+func (p *CursorResult[T]) ToGRPC() grpcResponse.PaginationData {
+	return grpcResponse.PaginationData{
+		Size:       int32(len(p.Items)),
+		Limit:      int32(p.Limit),
+		Results:    p.Items,
+		NextCursor: p.NextCursor,
+		PrevCursor: p.PrevCursor,
+		HasMore:    p.HasMore,
+	}
+}
+
+// cursorToken is the signed payload behind a cursor string.
+type cursorToken struct {
+	Sort   string            `json:"s"`
+	Prev   bool              `json:"p,omitempty"`
+	Values []json.RawMessage `json:"v"`
+}
+
+type keysetColumn struct {
+	field *schema.Field
+	desc  bool
+}
+
+// FindCursorCtx returns one keyset page of T. Unlike FindPageCtx it never counts and
+// its cost does not grow with the page depth.
+//
+//	page, err := orderRepo.FindCursorCtx(ctx, repository.CursorQuery{
+//		Cursor:       c.Query("cursor"),
+//		Limit:        50,
+//		Sort:         []repository.SortField{{Field: "created_at", Desc: true}},
+//		AllowedSorts: map[string]string{"created_at": ""},
+//	})
+//	httpResponse.CursorPaginationResponse(c, "successGetPagination", page.ToHTTP())
+func (r *Repository[T, ID]) FindCursorCtx(ctx context.Context, q CursorQuery, opts ...Option) (*CursorResult[T], error) {
+	limit := pageLimit(q.Limit)
+	o := newQueryOptions(opts)
+
+	columns, sortKey, err := r.keysetColumns(q)
+	if err != nil {
+		return nil, appError.InvalidOptionErrorCtx(ctx, r.TableName, err.Error())
+	}
+
+	db, err := applyFilters(q.Spec.scope(r.scoped(ctx, o).Model(new(T))), PageQuery{Filters: q.Filters, AllowedFilters: q.AllowedFilters})
+	if err != nil {
+		return nil, appError.InvalidOptionErrorCtx(ctx, r.TableName, err.Error())
+	}
+
+	prev := false
+	if q.Cursor != "" {
+		token, values, err := decodeCursor(q.Cursor, sortKey, columns)
+		if err != nil {
+			return nil, appError.InvalidOptionErrorCtx(ctx, "cursor", err.Error())
+		}
+		prev = token.Prev
+		db = db.Where(keysetExpr(columns, values, prev))
+	}
+
+	orderBy := clause.OrderBy{}
+	for _, col := range columns {
+		orderBy.Columns = append(orderBy.Columns, clause.OrderByColumn{
+			Column: clause.Column{Table: clause.CurrentTable, Name: col.field.DBName},
+			Desc:   col.desc != prev,
+		})
+	}
+
+	var items []*T
+	err = q.Spec.load(o.apply(db, true)).Clauses(orderBy).Limit(limit + 1).Find(&items).Error
+	if err != nil {
+		return nil, dbGorm.TranslateError(ctx, err, "errorFindResource", r.TableName)
+	}
+
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+	if prev {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	result := &CursorResult[T]{Items: items, Limit: limit, HasMore: hasMore}
+	if len(items) == 0 {
+		return result, nil
+	}
+	first, last := items[0], items[len(items)-1]
+	if hasMore || prev {
+		result.NextCursor = encodeCursor(ctx, sortKey, false, columns, last)
+	}
+	if (hasMore && prev) || (!prev && q.Cursor != "") {
+		result.PrevCursor = encodeCursor(ctx, sortKey, true, columns, first)
+	}
+	return result, nil
+}
+
+// keysetColumns resolves the whitelisted sort fields plus the primary key
+// columns and returns a signature binding cursors to this ordering.
+func (r *Repository[T, ID]) keysetColumns(q CursorQuery) ([]keysetColumn, string, error) {
+	sch, err := r.schema()
+	if err != nil {
+		return nil, "", err
+	}
+	if len(sch.PrimaryFields) == 0 {
+		return nil, "", fmt.Errorf("%s has no primary key for cursor pagination", sch.Name)
+	}
+
+	var columns []keysetColumn
+	sorted := map[*schema.Field]bool{}
+	for _, s := range q.Sort {
+		column, ok := whitelisted(q.AllowedSorts, s.Field)
+		if !ok {
+			return nil, "", fmt.Errorf("sort field %q is not allowed", s.Field)
+		}
+		field := sch.LookUpField(column)
+		if field == nil || field.DBName == "" {
+			return nil, "", fmt.Errorf("sort field %q cannot be used with a cursor", s.Field)
+		}
+		sorted[field] = true
+		columns = append(columns, keysetColumn{field: field, desc: s.Desc})
+	}
+	for _, pk := range sch.PrimaryFields {
+		if !sorted[pk] {
+			columns = append(columns, keysetColumn{field: pk})
+		}
+	}
+
+	parts := make([]string, len(columns))
+	for i, col := range columns {
+		parts[i] = col.field.DBName
+		if col.desc {
+			parts[i] += ":desc"
+		}
+	}
+	return columns, strings.Join(parts, ","), nil
+}
+
+// keysetExpr builds (a > ?) OR (a = ? AND b > ?) ... honouring each column's
+// direction; prev flips every comparison.
+func keysetExpr(columns []keysetColumn, values []any, prev bool) clause.Expression {
+	ors := make([]clause.Expression, 0, len(columns))
+	for i, col := range columns {
+		ands := make([]clause.Expression, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: columns[j].field.DBName}, Value: values[j]})
+		}
+		column := clause.Column{Table: clause.CurrentTable, Name: col.field.DBName}
+		if col.desc != prev {
+			ands = append(ands, clause.Lt{Column: column, Value: values[i]})
+		} else {
+			ands = append(ands, clause.Gt{Column: column, Value: values[i]})
+		}
+		ors = append(ors, clause.And(ands...))
+	}
+	return clause.Or(ors...)
+}
+
+// encodeCursor signs the keyset values of item.
+func encodeCursor(ctx context.Context, sortKey string, prev bool, columns []keysetColumn, item any) string {
+	rv := reflect.ValueOf(item)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+
+	token := cursorToken{Sort: sortKey, Prev: prev, Values: make([]json.RawMessage, len(columns))}
+	for i, col := range columns {
+		value, _ := col.field.ValueOf(ctx, rv)
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return ""
+		}
+		token.Values[i] = raw
+	}
+
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(signCursor(payload))
+}
+
+// decodeCursor verifies the signature and decodes the values into the column types.
+func decodeCursor(cursor, sortKey string, columns []keysetColumn) (cursorToken, []any, error) {
+	var token cursorToken
+	encoded, sig, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return token, nil, errInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return token, nil, errInvalidCursor
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, signCursor(payload)) {
+		return token, nil, errInvalidCursor
+	}
+	if err := json.Unmarshal(payload, &token); err != nil {
+		return token, nil, errInvalidCursor
+	}
+	if token.Sort != sortKey || len(token.Values) != len(columns) {
+		return token, nil, fmt.Errorf("cursor does not match the requested sort")
+	}
+
+	values := make([]any, len(columns))
+	for i, col := range columns {
+		target := reflect.New(col.field.FieldType)
+		if err := json.Unmarshal(token.Values[i], target.Interface()); err != nil {
+			return token, nil, errInvalidCursor
+		}
+		values[i] = target.Elem().Interface()
+	}
+	return token, values, nil
+}
+
+func signCursor(payload []byte) []byte {
+	mac := hmac.New(sha256.New, *cursorSecret.Load())
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
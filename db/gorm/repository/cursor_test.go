@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type cursorEvent struct {
+	ID        uint
+	Kind      string
+	CreatedAt time.Time
+}
+
+func cursorColumns(t *testing.T, repo *BaseRepository[cursorEvent], q CursorQuery) ([]keysetColumn, string) {
+	t.Helper()
+	columns, sortKey, err := repo.keysetColumns(q)
+	if err != nil {
+		t.Fatalf("keysetColumns: %v", err)
+	}
+	return columns, sortKey
+}
+
+var createdAtDesc = CursorQuery{
+	Sort:         []SortField{{Field: "created_at", Desc: true}},
+	AllowedSorts: map[string]string{"created_at": ""},
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	repo := NewBaseRepository[cursorEvent](openTestDB(t, &cursorEvent{}), "cursor_events")
+	columns, sortKey := cursorColumns(t, repo, createdAtDesc)
+	if sortKey != "created_at:desc,id" {
+		t.Fatalf("sort key = %q, want created_at:desc,id", sortKey)
+	}
+
+	created := time.Date(2025, 10, 8, 16, 26, 15, 0, time.UTC)
+	cursor := encodeCursor(ctx, sortKey, true, columns, &cursorEvent{ID: 42, CreatedAt: created})
+	if cursor == "" {
+		t.Fatal("encodeCursor returned an empty cursor")
+	}
+
+	token, values, err := decodeCursor(cursor, sortKey, columns)
+	if err != nil {
+		t.Fatalf("decodeCursor: %v", err)
+	}
+	if !token.Prev {
+		t.Fatal("token lost its prev flag")
+	}
+	if !reflect.DeepEqual(values, []any{created, uint(42)}) {
+		t.Fatalf("values = %#v, want [created 42]", values)
+	}
+}
+
+func TestCursorTampering(t *testing.T) {
+	ctx := context.Background()
+	repo := NewBaseRepository[cursorEvent](openTestDB(t, &cursorEvent{}), "cursor_events")
+	columns, sortKey := cursorColumns(t, repo, createdAtDesc)
+	cursor := encodeCursor(ctx, sortKey, false, columns, &cursorEvent{ID: 7, CreatedAt: time.Now()})
+	encoded, sig, _ := strings.Cut(cursor, ".")
+
+	// same signature, payload pointing at another row
+	payload, _ := base64.RawURLEncoding.DecodeString(encoded)
+	var token cursorToken
+	_ = json.Unmarshal(payload, &token)
+	token.Values[1] = json.RawMessage("1")
+	forged, _ := json.Marshal(token)
+
+	flipped := []byte(sig)
+	flipped[0] ^= 1
+
+	tests := map[string]string{
+		"forged payload":  base64.RawURLEncoding.EncodeToString(forged) + "." + sig,
+		"bad signature":   encoded + "." + string(flipped),
+		"no signature":    encoded,
+		"not base64":      "!!!." + sig,
+		"empty signature": encoded + ".",
+	}
+	for name, tampered := range tests {
+		if _, _, err := decodeCursor(tampered, sortKey, columns); !errors.Is(err, errInvalidCursor) {
+			t.Errorf("%s: err = %v, want errInvalidCursor", name, err)
+		}
+	}
+
+	ascColumns, ascKey := cursorColumns(t, repo, CursorQuery{
+		Sort:         []SortField{{Field: "created_at"}},
+		AllowedSorts: createdAtDesc.AllowedSorts,
+	})
+	if _, _, err := decodeCursor(cursor, ascKey, ascColumns); err == nil {
+		t.Error("cursor for another sort was accepted")
+	}
+}
+
+func TestCursorSecret(t *testing.T) {
+	ctx := context.Background()
+	repo := NewBaseRepository[cursorEvent](openTestDB(t, &cursorEvent{}), "cursor_events")
+	columns, sortKey := cursorColumns(t, repo, createdAtDesc)
+
+	previous := *cursorSecret.Load()
+	defer SetCursorSecret(previous)
+
+	SetCursorSecret([]byte("instance-a"))
+	cursor := encodeCursor(ctx, sortKey, false, columns, &cursorEvent{ID: 1, CreatedAt: time.Now()})
+
+	SetCursorSecret([]byte("instance-a"))
+	if _, _, err := decodeCursor(cursor, sortKey, columns); err != nil {
+		t.Fatalf("same secret: %v", err)
+	}
+	SetCursorSecret([]byte("instance-b"))
+	if _, _, err := decodeCursor(cursor, sortKey, columns); !errors.Is(err, errInvalidCursor) {
+		t.Fatalf("other secret: err = %v, want errInvalidCursor", err)
+	}
+}
+
+func TestFindCursorWalk(t *testing.T) {
+	ctx := context.Background()
+	repo := NewBaseRepository[cursorEvent](openTestDB(t, &cursorEvent{}), "cursor_events")
+
+	// pairs of equal timestamps exercise the primary key tie-breaker (ascending)
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 7; i++ {
+		if _, err := repo.CreateCtx(ctx, &cursorEvent{Kind: "k", CreatedAt: base.Add(time.Duration(i/2) * time.Hour)}); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+
+	q := createdAtDesc
+	q.Limit = 3
+	var forward []uint
+	var pages []*CursorResult[cursorEvent]
+	for {
+		page, err := repo.FindCursorCtx(ctx, q)
+		if err != nil {
+			t.Fatalf("FindCursor: %v", err)
+		}
+		pages = append(pages, page)
+		for _, e := range page.Items {
+			forward = append(forward, e.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		q.Cursor = page.NextCursor
+	}
+	if want := []uint{7, 5, 6, 3, 4, 1, 2}; !reflect.DeepEqual(forward, want) {
+		t.Fatalf("forward ids = %v, want %v", forward, want)
+	}
+
+	q.Cursor = pages[len(pages)-1].PrevCursor
+	back, err := repo.FindCursorCtx(ctx, q)
+	if err != nil {
+		t.Fatalf("FindCursor prev: %v", err)
+	}
+	var ids []uint
+	for _, e := range back.Items {
+		ids = append(ids, e.ID)
+	}
+	if want := []uint{3, 4, 1}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("previous page ids = %v, want %v", ids, want)
+	}
+
+	q.Cursor = pages[0].NextCursor + "x"
+	_, err = repo.FindCursorCtx(ctx, q)
+	assertErrorKey(t, err, "invalidOption")
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/clause"
+)
+
+// =======================================================
+// PRIMARY KEYS
+// =======================================================
+
+// keyExpr matches the row keyed by id. A composite key is read from the ID
+// struct fields named like T's primary key fields.
+func (r *Repository[T, ID]) keyExpr(id ID) (clause.Expression, error) {
+	sch, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+	if len(sch.PrimaryFields) <= 1 {
+		return clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: clause.PrimaryKey}, Value: id}, nil
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(id))
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s has a composite primary key, got ID %T", sch.Name, id)
+	}
+	exprs := make([]clause.Expression, 0, len(sch.PrimaryFields))
+	for _, pk := range sch.PrimaryFields {
+		value := rv.FieldByName(pk.Name)
+		if !value.IsValid() {
+			return nil, fmt.Errorf("ID %T has no field %s", id, pk.Name)
+		}
+		exprs = append(exprs, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName}, Value: value.Interface()})
+	}
+	return clause.And(exprs...), nil
+}
+
+// keysExpr matches the rows keyed by ids.
+func (r *Repository[T, ID]) keysExpr(ids []ID) (clause.Expression, error) {
+	sch, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+	if len(sch.PrimaryFields) <= 1 {
+		values := make([]any, len(ids))
+		for i, id := range ids {
+			values[i] = id
+		}
+		return clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: clause.PrimaryKey}, Values: values}, nil
+	}
+
+	exprs := make([]clause.Expression, 0, len(ids))
+	for _, id := range ids {
+		expr, err := r.keyExpr(id)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	return clause.Or(exprs...), nil
+}
+
+// idDetail formats id for error details.
+func (r *Repository[T, ID]) idDetail(id ID) string {
+	return fmt.Sprintf("%s id=%v", r.TableName, id)
+}
+
+func uniqueIDs[ID comparable](ids []ID) []ID {
+	seen := make(map[ID]struct{}, len(ids))
+	unique := make([]ID, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
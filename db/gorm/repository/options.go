@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// =======================================================
+// QUERY OPTIONS
+// =======================================================
+
+// Option customises a single repository call.
+type Option func(*queryOptions)
+
+type preload struct {
+	query string
+	args  []any
+}
+
+type trashedMode int
+
+const (
+	trashedExclude trashedMode = iota
+	trashedInclude
+	trashedOnly
+)
+
+type queryOptions struct {
+	preloads   []preload
+	selects    []string
+	locking    *clause.Locking
+	unscoped   bool
+	trashed    trashedMode
+	allTenants bool
+}
+
+// WithPreload eager loads an association, args are passed to gorm's Preload
+// (conditions or a func(*gorm.DB) *gorm.DB).
+func WithPreload(query string, args ...any) Option {
+	return func(o *queryOptions) {
+		o.preloads = append(o.preloads, preload{query: query, args: args})
+	}
+}
+
+// WithSelect limits the columns read, or written on Create / Update.
+func WithSelect(columns ...string) Option {
+	return func(o *queryOptions) {
+		o.selects = append(o.selects, columns...)
+	}
+}
+
+// WithForUpdate locks the selected rows (SELECT ... FOR UPDATE) until the
+// surrounding transaction ends. Without a transaction in ctx the lock is
+// released as soon as the statement finishes.
+func WithForUpdate() Option {
+	return func(o *queryOptions) {
+		o.locking = &clause.Locking{Strength: clause.LockingStrengthUpdate}
+	}
+}
+
+// WithForShare takes a shared row lock (SELECT ... FOR SHARE).
+func WithForShare() Option {
+	return func(o *queryOptions) {
+		o.locking = &clause.Locking{Strength: clause.LockingStrengthShare}
+	}
+}
+
+// WithUnscoped ignores gorm's soft delete scope: reads include deleted rows
+// and Delete removes the row permanently.
+func WithUnscoped() Option {
+	return func(o *queryOptions) {
+		o.unscoped = true
+	}
+}
+
+// WithTrashed includes soft deleted rows in reads, see FindDeletedCtx for only those.
+func WithTrashed() Option {
+	return func(o *queryOptions) {
+		o.trashed = trashedInclude
+	}
+}
+
+// WithAllTenants lifts tenant scoping for one call, for cross-tenant admin
+// queries. See AllTenants to lift it for everything using a context.
+func WithAllTenants() Option {
+	return func(o *queryOptions) {
+		o.allTenants = true
+	}
+}
+
+func newQueryOptions(opts []Option) *queryOptions {
+	o := &queryOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// apply adds the options that shape the statement to db (which rows match is
+// decided by Repository.scoped), locking is only used by reads.
+func (o *queryOptions) apply(db *gorm.DB, read bool) *gorm.DB {
+	if len(o.selects) > 0 {
+		db = db.Select(o.selects)
+	}
+	if !read {
+		return db
+	}
+	for _, p := range o.preloads {
+		db = db.Preload(p.query, p.args...)
+	}
+	if o.locking != nil {
+		db = db.Clauses(*o.locking)
+	}
+	return db
+}
@@ -0,0 +1,320 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	dbGorm "github.com/ginanjar-template-golang/shared-pkg/db/gorm"
+	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
+	grpcResponse "github.com/ginanjar-template-golang/shared-pkg/response/grpc_response"
+	httpResponse "github.com/ginanjar-template-golang/shared-pkg/response/http_response"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// =======================================================
+// PAGINATION
+// =======================================================
+
+var (
+	// DefaultPageLimit is used when PageQuery.Limit is not set
+	DefaultPageLimit = 10
+	// MaxPageLimit caps PageQuery.Limit
+	MaxPageLimit = 100
+	// MaxPage bounds PageQuery.Page (invalidOption past it), deep offsets are slow, use FindCursorCtx instead
+	MaxPage = 10000
+)
+
+// FilterOp is the comparison applied by a Filter.
+type FilterOp string
+
+const (
+	FilterEq     FilterOp = "eq"      // column = Value
+	FilterIn     FilterOp = "in"      // column IN Value (slice)
+	FilterLike   FilterOp = "like"    // column contains Value, % and _ match literally
+	FilterRange  FilterOp = "range"   // From <= column <= To, a nil bound is open
+	FilterIsNull FilterOp = "is_null" // column IS NULL, or IS NOT NULL when Value is false
+)
+
+// Filter narrows a page on one whitelisted field.
+type Filter struct {
+	Field string
+	Op    FilterOp
+	Value any
+	From  any
+	To    any
+}
+
+// SortField orders a page by one whitelisted field.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// PageQuery describes one page request. Sort and filter fields are public
+// names that must be listed in AllowedSorts / AllowedFilters, which map them to
+// columns (an empty column means the same name), so clients never reach
+// arbitrary columns.
+type PageQuery struct {
+	Page    int
+	Limit   int
+	Sort    []SortField
+	Filters []Filter
+	Spec    Spec // server side conditions, its OrderBy applies when Sort is empty
+
+	AllowedSorts   map[string]string
+	AllowedFilters map[string]string
+}
+
+// PageResult is one page of T plus the counters for the response packages.
+type PageResult[T any] struct {
+	Items     []*T
+	Page      int
+	Limit     int
+	TotalRow  int64
+	TotalPage int
+}
+
+// ToHTTP converts the page for httpResponse.PaginationResponse. Size is the
+// number of rows in this page. int(p.TotalRow) assumes a 64-bit int, on 32-bit
+// platforms counts past math.MaxInt32 would wrap.
+func (p *PageResult[T]) ToHTTP() httpResponse.Pagination {
+	return httpResponse.Pagination{
+		Page:     p.Page,
+		Size:     len(p.Items),
+		Limit:    p.Limit,
+		TotalRow: int(p.TotalRow),
+		Results:  p.Items,
+		HasMore:  p.Page < p.TotalPage,
+	}
+}
+
+// ToGRPC converts the page for grpcResponse.PaginationSuccess. TotalRow
+// saturates at math.MaxInt32 instead of wrapping.
+func (p *PageResult[T]) ToGRPC() grpcResponse.PaginationData {
+	return grpcResponse.PaginationData{
+		Page:     int32(p.Page),
+		Size:     int32(len(p.Items)),
+		Limit:    int32(p.Limit),
+		TotalRow: int32(min(p.TotalRow, math.MaxInt32)),
+		Results:  p.Items,
+		HasMore:  p.Page < p.TotalPage,
+	}
+}
+
+// normalize defaults page and limit and clamps the limit. A page past MaxPage,
+// or one whose offset overflows an int32 (PaginationData) with the caps
+// disabled, is an error rather than silently becoming another page.
+func (q PageQuery) normalize() (PageQuery, error) {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	q.Limit = pageLimit(q.Limit)
+	if MaxPage > 0 && q.Page > MaxPage {
+		return q, fmt.Errorf("page %d is past the last page %d, use FindCursorCtx for deep pages", q.Page, MaxPage)
+	}
+	if q.Page > max(1, math.MaxInt32/q.Limit) {
+		return q, fmt.Errorf("page %d with limit %d is out of range", q.Page, q.Limit)
+	}
+	return q, nil
+}
+
+// pageLimit defaults and caps a page size.
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		limit = DefaultPageLimit
+	}
+	if MaxPageLimit > 0 && limit > MaxPageLimit {
+		limit = MaxPageLimit
+	}
+	return min(limit, math.MaxInt32)
+}
+
+// FindPageCtx returns one page of T matching q's filters, ordered by q's sort
+// (primary key when empty), together with the total row count.
+//
+//	page, err := userRepo.FindPageCtx(ctx, repository.PageQuery{
+//		Page: 1, Limit: 20,
+//		Sort:    []repository.SortField{{Field: "created_at", Desc: true}},
+//		Filters: []repository.Filter{{Field: "status", Op: repository.FilterIn, Value: []string{"active", "pending"}}},
+//		AllowedSorts:   map[string]string{"created_at": ""},
+//		AllowedFilters: map[string]string{"status": ""},
+//	})
+//	httpResponse.PaginationResponse(c, "successGetPagination", page.ToHTTP())
+func (r *Repository[T, ID]) FindPageCtx(ctx context.Context, q PageQuery, opts ...Option) (*PageResult[T], error) {
+	q, err := q.normalize()
+	if err != nil {
+		return nil, appError.InvalidOptionErrorCtx(ctx, r.TableName, err.Error())
+	}
+	o := newQueryOptions(opts)
+
+	orderBy, err := r.sortClause(q)
+	if err != nil {
+		return nil, appError.InvalidOptionErrorCtx(ctx, r.TableName, err.Error())
+	}
+	filtered, err := applyFilters(q.Spec.scope(r.scoped(ctx, o).Model(new(T))), q)
+	if err != nil {
+		return nil, appError.InvalidOptionErrorCtx(ctx, r.TableName, err.Error())
+	}
+
+	var total int64
+	if err := filtered.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, dbGorm.TranslateError(ctx, err, "errorFindResource", r.TableName+" count")
+	}
+
+	items := make([]*T, 0, q.Limit)
+	if total > int64((q.Page-1)*q.Limit) {
+		db := q.Spec.load(o.apply(filtered.Session(&gorm.Session{}), true)).Clauses(orderBy)
+		err := db.Offset((q.Page - 1) * q.Limit).Limit(q.Limit).Find(&items).Error
+		if err != nil {
+			return nil, dbGorm.TranslateError(ctx, err, "errorFindResource", r.TableName)
+		}
+	}
+
+	totalPage := int(total) / q.Limit
+	if int(total)%q.Limit != 0 {
+		totalPage++
+	}
+
+	return &PageResult[T]{
+		Items:     items,
+		Page:      q.Page,
+		Limit:     q.Limit,
+		TotalRow:  total,
+		TotalPage: totalPage,
+	}, nil
+}
+
+// whitelisted resolves a public field name to its column.
+func whitelisted(allowed map[string]string, field string) (string, bool) {
+	column, ok := allowed[field]
+	if !ok {
+		return "", false
+	}
+	if column == "" {
+		column = field
+	}
+	return column, true
+}
+
+// sortClause builds ORDER BY from q.Sort, else q.Spec's OrderBy, followed by
+// the primary key so rows with equal sort values keep a stable order across
+// pages.
+func (r *Repository[T, ID]) sortClause(q PageQuery) (clause.OrderBy, error) {
+	orderBy := clause.OrderBy{}
+	sorted := map[string]bool{}
+	if len(q.Sort) == 0 {
+		orderBy.Columns = append(orderBy.Columns, q.Spec.orders...)
+		for _, col := range q.Spec.orders {
+			sorted[col.Column.Name] = true
+		}
+	}
+	for _, s := range q.Sort {
+		column, ok := whitelisted(q.AllowedSorts, s.Field)
+		if !ok {
+			return orderBy, fmt.Errorf("sort field %q is not allowed", s.Field)
+		}
+		sorted[column] = true
+		orderBy.Columns = append(orderBy.Columns, clause.OrderByColumn{
+			Column: clause.Column{Name: column},
+			Desc:   s.Desc,
+		})
+	}
+
+	sch, err := r.schema()
+	if err != nil {
+		return orderBy, err
+	}
+	if len(sch.PrimaryFields) == 0 {
+		if len(orderBy.Columns) == 0 {
+			orderBy.Columns = []clause.OrderByColumn{{Column: clause.Column{Table: clause.CurrentTable, Name: clause.PrimaryKey}}}
+		}
+		return orderBy, nil
+	}
+	for _, pk := range sch.PrimaryFields {
+		if !sorted[pk.DBName] {
+			orderBy.Columns = append(orderBy.Columns, clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName}})
+		}
+	}
+	return orderBy, nil
+}
+
+// applyFilters adds one WHERE condition per filter. Columns are quoted by gorm
+// and values bound as parameters.
+func applyFilters(db *gorm.DB, q PageQuery) (*gorm.DB, error) {
+	for _, f := range q.Filters {
+		column, ok := whitelisted(q.AllowedFilters, f.Field)
+		if !ok {
+			return nil, fmt.Errorf("filter field %q is not allowed", f.Field)
+		}
+		expr, err := filterExpr(clause.Column{Name: column}, f)
+		if err != nil {
+			return nil, err
+		}
+		if expr != nil {
+			db = db.Where(expr)
+		}
+	}
+	return db, nil
+}
+
+func filterExpr(column clause.Column, f Filter) (clause.Expression, error) {
+	switch f.Op {
+	case FilterEq, "":
+		return clause.Eq{Column: column, Value: f.Value}, nil
+	case FilterIn:
+		values := toSlice(f.Value)
+		if len(values) == 0 {
+			return nil, fmt.Errorf("filter %q needs at least one value", f.Field)
+		}
+		return clause.IN{Column: column, Values: values}, nil
+	case FilterLike:
+		pattern := "%" + likeEscaper.Replace(fmt.Sprint(f.Value)) + "%"
+		return clause.Expr{SQL: "? LIKE ? ESCAPE '" + likeEscape + "'", Vars: []any{column, pattern}}, nil
+	case FilterRange:
+		var exprs []clause.Expression
+		if f.From != nil {
+			exprs = append(exprs, clause.Gte{Column: column, Value: f.From})
+		}
+		if f.To != nil {
+			exprs = append(exprs, clause.Lte{Column: column, Value: f.To})
+		}
+		if len(exprs) == 0 {
+			return nil, nil
+		}
+		return clause.And(exprs...), nil
+	case FilterIsNull:
+		if isNull, ok := f.Value.(bool); ok && !isNull {
+			return clause.Neq{Column: column, Value: nil}, nil
+		}
+		return clause.Eq{Column: column, Value: nil}, nil
+	default:
+		return nil, fmt.Errorf("filter operator %q is not supported", f.Op)
+	}
+}
+
+// likeEscape is the LIKE escape character; unlike backslash it is written
+// the same in MySQL, PostgreSQL and SQLite string literals.
+const likeEscape = "!"
+
+// likeEscaper makes client values match literally inside a LIKE pattern.
+var likeEscaper = strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_")
+
+// toSlice spreads a slice or array value, anything else becomes a single value.
+func toSlice(value any) []any {
+	if value == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []any{value}
+	}
+	out := make([]any, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+type pagedUser struct {
+	ID     uint
+	Name   string
+	Status string
+}
+
+func seedPagedUsers(t *testing.T, repo *BaseRepository[pagedUser], users ...pagedUser) {
+	t.Helper()
+	for i := range users {
+		if _, err := repo.CreateCtx(context.Background(), &users[i]); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+}
+
+func TestFindPageSortTieBreaker(t *testing.T) {
+	ctx := context.Background()
+	repo := NewBaseRepository[pagedUser](openTestDB(t, &pagedUser{}), "paged_users")
+	for i := 0; i < 9; i++ {
+		seedPagedUsers(t, repo, pagedUser{Name: "same", Status: "active"})
+	}
+
+	seen := map[uint]bool{}
+	for page := 1; page <= 3; page++ {
+		result, err := repo.FindPageCtx(ctx, PageQuery{
+			Page: page, Limit: 3,
+			Sort:         []SortField{{Field: "status", Desc: true}},
+			AllowedSorts: map[string]string{"status": ""},
+		})
+		if err != nil {
+			t.Fatalf("page %d: %v", page, err)
+		}
+		for _, u := range result.Items {
+			if seen[u.ID] {
+				t.Fatalf("page %d repeats id %d", page, u.ID)
+			}
+			seen[u.ID] = true
+		}
+	}
+	if len(seen) != 9 {
+		t.Fatalf("pages returned %d distinct rows, want 9", len(seen))
+	}
+}
+
+func TestFindPageLikeMatchesLiterally(t *testing.T) {
+	ctx := context.Background()
+	repo := NewBaseRepository[pagedUser](openTestDB(t, &pagedUser{}), "paged_users")
+	seedPagedUsers(t, repo,
+		pagedUser{Name: "john_doe"},
+		pagedUser{Name: "mr john_doe jr"},
+		pagedUser{Name: "johnXdoe"},
+		pagedUser{Name: "100% sure"},
+		pagedUser{Name: "1000 sure"},
+		pagedUser{Name: `back\slash!`},
+	)
+
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"john_doe", 2},
+		{"100%", 1},
+		{"%", 1},
+		{"_", 2},
+		{`\slash`, 1},
+		{"!", 1},
+		{"sure", 2},
+	}
+	for _, tt := range tests {
+		result, err := repo.FindPageCtx(ctx, PageQuery{
+			Filters:        []Filter{{Field: "name", Op: FilterLike, Value: tt.value}},
+			AllowedFilters: map[string]string{"name": ""},
+		})
+		if err != nil {
+			t.Fatalf("like %q: %v", tt.value, err)
+		}
+		if result.TotalRow != int64(tt.want) {
+			t.Errorf("like %q matched %d rows, want %d", tt.value, result.TotalRow, tt.want)
+		}
+	}
+}
+
+func TestFindPageRejectsOutOfRangePage(t *testing.T) {
+	db := openTestDB(t, &pagedUser{})
+	repo := NewBaseRepository[pagedUser](db, "paged_users")
+
+	_, err := repo.FindPageCtx(context.Background(), PageQuery{Page: MaxPage + 1, Limit: 10})
+	assertErrorKey(t, err, "invalidOption")
+
+	if _, err := repo.FindPageCtx(context.Background(), PageQuery{Page: MaxPage, Limit: 10}); err != nil {
+		t.Fatalf("last allowed page: %v", err)
+	}
+}
+
+func TestPageQueryNormalizeKeepsOffsetInInt32(t *testing.T) {
+	defer func(maxPage, maxLimit int) { MaxPage, MaxPageLimit = maxPage, maxLimit }(MaxPage, MaxPageLimit)
+	MaxPage, MaxPageLimit = 0, 0
+
+	if _, err := (PageQuery{Page: math.MaxInt, Limit: math.MaxInt / 2}).normalize(); err == nil {
+		t.Fatal("normalize accepted a page whose offset overflows int32")
+	}
+	q, err := PageQuery{Page: 1000, Limit: 1000}.normalize()
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if offset := int64(q.Page-1) * int64(q.Limit); offset < 0 || offset > math.MaxInt32 {
+		t.Fatalf("offset = %d with caps disabled, want within int32", offset)
+	}
+}
+
+func TestPageResultToGRPCSaturatesTotalRow(t *testing.T) {
+	page := &PageResult[pagedUser]{Page: 1, Limit: 10, TotalRow: math.MaxInt32 + 5, TotalPage: 2}
+	if got := page.ToGRPC().TotalRow; got != math.MaxInt32 {
+		t.Fatalf("TotalRow = %d, want %d", got, int32(math.MaxInt32))
+	}
+	if got := page.ToHTTP().TotalRow; int64(got) != page.TotalRow {
+		t.Fatalf("http TotalRow = %d, want %d", got, page.TotalRow)
+	}
+}
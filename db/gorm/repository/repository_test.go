@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"testing"
+
+	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	_ IRepository[pagedUser, uint] = (*BaseRepository[pagedUser])(nil)
+	_ IBaseRepository[pagedUser]   = (*BaseRepository[pagedUser])(nil)
+)
+
+// openTestDB opens a private in-memory SQLite database migrated for models.
+func openTestDB(t *testing.T, models ...any) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("sql db: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1) // every connection would get its own memory database
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// assertErrorKey fails unless err is an AppError with the message key want.
+func assertErrorKey(t *testing.T, err error, want string) {
+	t.Helper()
+	appErr, ok := appError.FromError(err)
+	if !ok {
+		t.Fatalf("error = %v, want AppError %q", err, want)
+	}
+	if appErr.MessageKey != want {
+		t.Fatalf("error key = %q, want %q (%v)", appErr.MessageKey, want, err)
+	}
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	dbGorm "github.com/ginanjar-template-golang/shared-pkg/db/gorm"
+	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// =======================================================
+// SOFT DELETE, RESTORE & PURGE
+// =======================================================
+
+// SoftDeleteColumn marks rows as deleted through a plain column instead of
+// gorm.DeletedAt, e.g. a status or a nullable timestamp:
+//
+//	repo.SoftDeleteColumn = &repository.SoftDeleteColumn{Name: "status", DeletedValue: "deleted", ActiveValue: "active"}
+//	repo.SoftDeleteColumn = &repository.SoftDeleteColumn{Name: "archived_at"} // time.Now() / NULL
+type SoftDeleteColumn struct {
+	Name         string
+	DeletedValue any // stored by SoftDeleteCtx, nil stores the current time
+	ActiveValue  any // stored by RestoreCtx and required by reads, nil means NULL
+}
+
+var deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+
+// trashedExpr matches deleted (or live) rows, nil when T cannot be soft deleted.
+func (r *Repository[T, ID]) trashedExpr(deleted bool) clause.Expression {
+	if sd := r.SoftDeleteColumn; sd != nil {
+		column := clause.Column{Table: clause.CurrentTable, Name: sd.Name}
+		if deleted {
+			return clause.Neq{Column: column, Value: sd.ActiveValue}
+		}
+		return clause.Eq{Column: column, Value: sd.ActiveValue}
+	}
+
+	if column := r.deletedAtColumn(); column != "" {
+		c := clause.Column{Table: clause.CurrentTable, Name: column}
+		if deleted {
+			return clause.Neq{Column: c, Value: nil}
+		}
+		return clause.Eq{Column: c, Value: nil}
+	}
+	return nil
+}
+
+// deletedAtColumn returns the column of T's gorm.DeletedAt field, "" when it has none.
+func (r *Repository[T, ID]) deletedAtColumn() string {
+	sch, err := r.schema()
+	if err != nil {
+		return ""
+	}
+	for _, field := range sch.Fields {
+		if field.FieldType == deletedAtType && field.DBName != "" {
+			return field.DBName
+		}
+	}
+	return ""
+}
+
+// noSoftDelete is the invalidOption returned when a soft delete operation is
+// used on a T without gorm.DeletedAt or SoftDeleteColumn.
+func (r *Repository[T, ID]) noSoftDelete(ctx context.Context) error {
+	return appError.InvalidOptionErrorCtx(ctx, r.TableName, r.TableName+" has no soft delete column")
+}
+
+// DeleteCtx soft deletes when T supports it (gorm.DeletedAt or SoftDeleteColumn),
+// otherwise, or WithUnscoped, deletes permanently.
+func (r *Repository[T, ID]) DeleteCtx(ctx context.Context, id ID, opts ...Option) error {
+	o := newQueryOptions(opts)
+	if o.unscoped || r.trashedExpr(true) == nil {
+		return r.hardDelete(ctx, id, o)
+	}
+	return r.SoftDeleteCtx(ctx, id, opts...)
+}
+
+// SoftDeleteCtx marks the live row id as deleted, recordNotFound when there is none.
+func (r *Repository[T, ID]) SoftDeleteCtx(ctx context.Context, id ID, opts ...Option) error {
+	detail := r.idDetail(id)
+	cond, err := r.keyExpr(id)
+	if err != nil {
+		return dbGorm.TranslateError(ctx, err, "errorDeleteResource", detail)
+	}
+	result := r.softDelete(ctx, newQueryOptions(opts), cond)
+	if result == nil {
+		return r.noSoftDelete(ctx)
+	}
+	return r.affected(ctx, result, "errorDeleteResource", detail)
+}
+
+// softDelete marks the live rows matching cond as deleted, nil when T cannot be soft deleted.
+func (r *Repository[T, ID]) softDelete(ctx context.Context, o *queryOptions, cond clause.Expression) *gorm.DB {
+	o.unscoped, o.trashed = false, trashedExclude
+	db := r.scoped(ctx, o).Where(cond)
+
+	switch {
+	case r.SoftDeleteColumn != nil:
+		value := r.SoftDeleteColumn.DeletedValue
+		if value == nil {
+			value = time.Now()
+		}
+		return db.Model(new(T)).Update(r.SoftDeleteColumn.Name, value)
+	case r.deletedAtColumn() != "":
+		return db.Delete(new(T))
+	default:
+		return nil
+	}
+}
+
+// RestoreCtx brings the soft deleted row id back, recordNotFound when no deleted row matches.
+func (r *Repository[T, ID]) RestoreCtx(ctx context.Context, id ID, opts ...Option) error {
+	if r.trashedExpr(true) == nil {
+		return r.noSoftDelete(ctx)
+	}
+	detail := r.idDetail(id)
+	cond, err := r.keyExpr(id)
+	if err != nil {
+		return dbGorm.TranslateError(ctx, err, "errorUpdateResource", detail)
+	}
+	o := newQueryOptions(opts)
+	o.unscoped, o.trashed = false, trashedOnly
+	db := r.scoped(ctx, o).Model(new(T)).Where(cond)
+
+	var result *gorm.DB
+	if r.SoftDeleteColumn != nil {
+		result = db.Update(r.SoftDeleteColumn.Name, r.SoftDeleteColumn.ActiveValue)
+	} else {
+		result = db.Update(r.deletedAtColumn(), nil)
+	}
+	return r.affected(ctx, result, "errorUpdateResource", detail)
+}
+
+// ForceDeleteCtx removes the soft deletable row id permanently, deleted or not.
+// Models without soft delete get invalidOption, DeleteCtx already removes
+// their rows permanently.
+func (r *Repository[T, ID]) ForceDeleteCtx(ctx context.Context, id ID, opts ...Option) error {
+	if r.trashedExpr(true) == nil {
+		return r.noSoftDelete(ctx)
+	}
+	return r.hardDelete(ctx, id, newQueryOptions(opts))
+}
+
+// hardDelete removes the row id permanently, deleted or not.
+func (r *Repository[T, ID]) hardDelete(ctx context.Context, id ID, o *queryOptions) error {
+	detail := r.idDetail(id)
+	cond, err := r.keyExpr(id)
+	if err != nil {
+		return dbGorm.TranslateError(ctx, err, "errorDeleteResource", detail)
+	}
+	o.unscoped = true
+	result := r.scoped(ctx, o).Where(cond).Delete(new(T))
+	return r.affected(ctx, result, "errorDeleteResource", detail)
+}
+
+// FindDeletedCtx returns only soft deleted rows.
+func (r *Repository[T, ID]) FindDeletedCtx(ctx context.Context, opts ...Option) ([]*T, error) {
+	var results []*T
+	o := newQueryOptions(opts)
+	o.unscoped, o.trashed = false, trashedOnly
+	err := o.apply(r.scoped(ctx, o), true).Find(&results).Error
+	if err != nil {
+		return nil, dbGorm.TranslateError(ctx, err, "errorFindResource", r.TableName)
+	}
+	return results, nil
+}
+
+// affected translates result's error and reports recordNotFound when no row changed.
+func (r *Repository[T, ID]) affected(ctx context.Context, result *gorm.DB, fallbackKey, detail string) error {
+	if result.Error != nil {
+		return dbGorm.TranslateError(ctx, result.Error, fallbackKey, detail)
+	}
+	if result.RowsAffected == 0 {
+		return appError.NewCtx(ctx, dbGorm.KeyRecordNotFound, detail, nil)
+	}
+	return nil
+}
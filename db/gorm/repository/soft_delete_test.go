@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	dbGorm "github.com/ginanjar-template-golang/shared-pkg/db/gorm"
+	"gorm.io/gorm"
+)
+
+type trashDoc struct {
+	ID        uint
+	Title     string
+	DeletedAt gorm.DeletedAt
+}
+
+type statusDoc struct {
+	ID     uint
+	Title  string
+	Status string `gorm:"default:active"`
+}
+
+type plainDoc struct {
+	ID    uint
+	Title string
+}
+
+func docTitles[T any](t *testing.T, rows []*T, title func(*T) string) []string {
+	t.Helper()
+	out := make([]string, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, title(row))
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestSoftDeleteDeletedAt(t *testing.T) {
+	ctx := context.Background()
+	repo := NewBaseRepository[trashDoc](openTestDB(t, &trashDoc{}), "trash_docs")
+	title := func(d *trashDoc) string { return d.Title }
+
+	live, _ := repo.CreateCtx(ctx, &trashDoc{Title: "live"})
+	gone, _ := repo.CreateCtx(ctx, &trashDoc{Title: "gone"})
+
+	if err := repo.SoftDeleteCtx(ctx, gone.ID); err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+	assertErrorKey(t, repo.SoftDeleteCtx(ctx, gone.ID), dbGorm.KeyRecordNotFound)
+
+	rows, _ := repo.FindAllCtx(ctx)
+	if got := docTitles(t, rows, title); !slices.Equal(got, []string{"live"}) {
+		t.Fatalf("FindAllCtx = %v, want [live]", got)
+	}
+	rows, _ = repo.FindAllCtx(ctx, WithTrashed())
+	if got := docTitles(t, rows, title); !slices.Equal(got, []string{"gone", "live"}) {
+		t.Fatalf("FindAllCtx(WithTrashed) = %v, want [gone live]", got)
+	}
+	rows, _ = repo.FindDeletedCtx(ctx)
+	if got := docTitles(t, rows, title); !slices.Equal(got, []string{"gone"}) {
+		t.Fatalf("FindDeletedCtx = %v, want [gone]", got)
+	}
+
+	assertErrorKey(t, repo.RestoreCtx(ctx, live.ID), dbGorm.KeyRecordNotFound)
+	if err := repo.RestoreCtx(ctx, gone.ID); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if _, err := repo.FindByIDCtx(ctx, gone.ID); err != nil {
+		t.Fatalf("find restored: %v", err)
+	}
+
+	// ForceDeleteCtx removes live and soft deleted rows alike
+	if err := repo.SoftDeleteCtx(ctx, gone.ID); err != nil {
+		t.Fatalf("soft delete again: %v", err)
+	}
+	for _, id := range []uint{live.ID, gone.ID} {
+		if err := repo.ForceDeleteCtx(ctx, id); err != nil {
+			t.Fatalf("force delete %d: %v", id, err)
+		}
+	}
+	rows, _ = repo.FindAllCtx(ctx, WithTrashed())
+	if len(rows) != 0 {
+		t.Fatalf("rows after force delete = %d, want 0", len(rows))
+	}
+	assertErrorKey(t, repo.ForceDeleteCtx(ctx, gone.ID), dbGorm.KeyRecordNotFound)
+}
+
+func TestSoftDeleteColumn(t *testing.T) {
+	ctx := context.Background()
+	repo := NewBaseRepository[statusDoc](openTestDB(t, &statusDoc{}), "status_docs")
+	repo.SoftDeleteColumn = &SoftDeleteColumn{Name: "status", DeletedValue: "deleted", ActiveValue: "active"}
+	title := func(d *statusDoc) string { return d.Title }
+
+	if _, err := repo.CreateCtx(ctx, &statusDoc{Title: "live"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	gone, _ := repo.CreateCtx(ctx, &statusDoc{Title: "gone"})
+
+	if err := repo.DeleteCtx(ctx, gone.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	stored, err := repo.FindByIDCtx(ctx, gone.ID, WithTrashed())
+	if err != nil || stored.Status != "deleted" {
+		t.Fatalf("deleted row = %+v, %v, want status deleted", stored, err)
+	}
+	_, err = repo.FindByIDCtx(ctx, gone.ID)
+	assertErrorKey(t, err, dbGorm.KeyRecordNotFound)
+
+	rows, _ := repo.FindDeletedCtx(ctx)
+	if got := docTitles(t, rows, title); !slices.Equal(got, []string{"gone"}) {
+		t.Fatalf("FindDeletedCtx = %v, want [gone]", got)
+	}
+	if err := repo.RestoreCtx(ctx, gone.ID); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	rows, _ = repo.FindAllCtx(ctx)
+	if got := docTitles(t, rows, title); !slices.Equal(got, []string{"gone", "live"}) {
+		t.Fatalf("FindAllCtx after restore = %v, want [gone live]", got)
+	}
+}
+
+func TestSoftDeleteWithoutColumnIsInvalidOption(t *testing.T) {
+	ctx := context.Background()
+	repo := NewBaseRepository[plainDoc](openTestDB(t, &plainDoc{}), "plain_docs")
+	doc, err := repo.CreateCtx(ctx, &plainDoc{Title: "a"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"SoftDeleteCtx", func() error { return repo.SoftDeleteCtx(ctx, doc.ID) }},
+		{"RestoreCtx", func() error { return repo.RestoreCtx(ctx, doc.ID) }},
+		{"ForceDeleteCtx", func() error { return repo.ForceDeleteCtx(ctx, doc.ID) }},
+		{"FindDeletedCtx", func() error { _, err := repo.FindDeletedCtx(ctx); return err }},
+		{"FindAllCtx WithTrashed", func() error { _, err := repo.FindAllCtx(ctx, WithTrashed()); return err }},
+		{"FindByIDCtx WithTrashed", func() error { _, err := repo.FindByIDCtx(ctx, doc.ID, WithTrashed()); return err }},
+		{"CountCtx WithTrashed", func() error { _, err := repo.CountCtx(ctx, Spec{}, WithTrashed()); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertErrorKey(t, tt.call(), "invalidOption")
+		})
+	}
+
+	// nothing above touched the row, DeleteCtx still removes it permanently
+	if _, err := repo.FindByIDCtx(ctx, doc.ID); err != nil {
+		t.Fatalf("find after refused calls: %v", err)
+	}
+	if err := repo.DeleteCtx(ctx, doc.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	_, err = repo.FindByIDCtx(ctx, doc.ID, WithUnscoped())
+	assertErrorKey(t, err, dbGorm.KeyRecordNotFound)
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	dbGorm "github.com/ginanjar-template-golang/shared-pkg/db/gorm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// =======================================================
+// SPECIFICATIONS
+// =======================================================
+
+// Spec is a composable query for FindOneCtx, FindManyCtx, CountCtx, ExistsCtx and
+// FindPageCtx / FindCursorCtx (PageQuery.Spec), so complex queries stay inside the
+// repository and still return AppErrors. The zero Spec matches every row.
+// Specs are immutable, every method returns a copy.
+//
+//	active := repository.Where("status = ?", "active")
+//	recent := repository.Where("created_at > ?", since)
+//	spec := repository.And(active, repository.Or(recent, repository.Where(map[string]any{"vip": true}))).
+//		Joins("Customer").
+//		Preload("Items").
+//		OrderBy("created_at", true)
+//	orders, err := orderRepo.FindManyCtx(ctx, spec)
+type Spec struct {
+	cond     *condition
+	joins    []join
+	preloads []preload
+	orders   []clause.OrderByColumn
+}
+
+type join struct {
+	query string
+	args  []any
+}
+
+type condOp int
+
+const (
+	condWhere condOp = iota
+	condAnd
+	condOr
+	condNot
+)
+
+// condition is a tree of gorm Where arguments, built against the statement
+// when the query runs so every gorm condition form (string, map, struct,
+// clause.Expression) is supported.
+type condition struct {
+	op       condOp
+	query    any
+	args     []any
+	children []*condition
+}
+
+func (c *condition) build(stmt *gorm.Statement) clause.Expression {
+	switch c.op {
+	case condAnd, condOr:
+		exprs := make([]clause.Expression, 0, len(c.children))
+		for _, child := range c.children {
+			exprs = append(exprs, child.build(stmt))
+		}
+		if c.op == condOr {
+			return clause.Or(exprs...)
+		}
+		return clause.And(exprs...)
+	case condNot:
+		return clause.Not(c.children[0].build(stmt))
+	default:
+		return clause.And(stmt.BuildCondition(c.query, c.args...)...)
+	}
+}
+
+// Where matches rows with gorm Where arguments: "status = ?", map[string]any,
+// a struct or a clause.Expression.
+func Where(query any, args ...any) Spec {
+	return Spec{cond: &condition{query: query, args: args}}
+}
+
+// And matches rows matched by every spec; joins, preloads and orders are merged.
+func And(specs ...Spec) Spec {
+	return combine(condAnd, specs)
+}
+
+// Or matches rows matched by any spec; joins, preloads and orders are merged.
+// A spec without conditions matches every row, and so does the result.
+func Or(specs ...Spec) Spec {
+	return combine(condOr, specs)
+}
+
+// Not matches rows spec does not match, a spec without conditions matches none.
+func Not(spec Spec) Spec {
+	if spec.cond == nil {
+		spec.cond = &condition{query: "1 = 0"}
+		return spec
+	}
+	spec.cond = &condition{op: condNot, children: []*condition{spec.cond}}
+	return spec
+}
+
+// OrderBy sorts by column ("created_at", "orders.created_at"), applied in call order.
+func OrderBy(column string, desc bool) Spec {
+	return Spec{}.OrderBy(column, desc)
+}
+
+// Preload eager loads an association, args as in gorm's Preload.
+func Preload(query string, args ...any) Spec {
+	return Spec{}.Preload(query, args...)
+}
+
+// Joins joins an association ("Customer") or a raw JOIN clause, args as in gorm's Joins.
+func Joins(query string, args ...any) Spec {
+	return Spec{}.Joins(query, args...)
+}
+
+// Where narrows s with another condition (AND).
+func (s Spec) Where(query any, args ...any) Spec {
+	return And(s, Where(query, args...))
+}
+
+func (s Spec) OrderBy(column string, desc bool) Spec {
+	s.orders = append(slices.Clip(s.orders), clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: desc})
+	return s
+}
+
+func (s Spec) Preload(query string, args ...any) Spec {
+	s.preloads = append(slices.Clip(s.preloads), preload{query: query, args: args})
+	return s
+}
+
+func (s Spec) Joins(query string, args ...any) Spec {
+	s.joins = append(slices.Clip(s.joins), join{query: query, args: args})
+	return s
+}
+
+func combine(op condOp, specs []Spec) Spec {
+	var out Spec
+	var children []*condition
+	matchAll := false
+	for _, s := range specs {
+		out.joins = append(out.joins, s.joins...)
+		out.preloads = append(out.preloads, s.preloads...)
+		out.orders = append(out.orders, s.orders...)
+		if s.cond == nil {
+			matchAll = true
+			continue
+		}
+		children = append(children, s.cond)
+	}
+
+	switch {
+	case len(children) == 0 || (op == condOr && matchAll):
+		return out
+	case len(children) == 1:
+		out.cond = children[0]
+	default:
+		out.cond = &condition{op: op, children: children}
+	}
+	return out
+}
+
+// scope adds the joins and conditions of s to db.
+func (s Spec) scope(db *gorm.DB) *gorm.DB {
+	for _, j := range s.joins {
+		db = db.Joins(j.query, j.args...)
+	}
+	if s.cond != nil {
+		db = db.Where(s.cond.build(db.Statement))
+	}
+	return db
+}
+
+// load adds the preloads of s to db.
+func (s Spec) load(db *gorm.DB) *gorm.DB {
+	for _, p := range s.preloads {
+		db = db.Preload(p.query, p.args...)
+	}
+	return db
+}
+
+// query returns the read statement for spec with opts applied.
+func (r *Repository[T, ID]) query(ctx context.Context, spec Spec, opts []Option) *gorm.DB {
+	o := newQueryOptions(opts)
+	db := spec.load(spec.scope(o.apply(r.scoped(ctx, o).Model(new(T)), true)))
+	if len(spec.orders) > 0 {
+		db = db.Clauses(clause.OrderBy{Columns: spec.orders})
+	}
+	return db
+}
+
+// FindOneCtx returns the first row matching spec, recordNotFound when there is none.
+func (r *Repository[T, ID]) FindOneCtx(ctx context.Context, spec Spec, opts ...Option) (*T, error) {
+	var result T
+	err := r.query(ctx, spec, opts).First(&result).Error
+	if err != nil {
+		return nil, dbGorm.TranslateError(ctx, err, "errorFindResource", r.TableName)
+	}
+	return &result, nil
+}
+
+// FindManyCtx returns every row matching spec.
+func (r *Repository[T, ID]) FindManyCtx(ctx context.Context, spec Spec, opts ...Option) ([]*T, error) {
+	var results []*T
+	err := r.query(ctx, spec, opts).Find(&results).Error
+	if err != nil {
+		return nil, dbGorm.TranslateError(ctx, err, "errorFindResource", r.TableName)
+	}
+	return results, nil
+}
+
+// CountCtx returns the number of rows matching spec.
+func (r *Repository[T, ID]) CountCtx(ctx context.Context, spec Spec, opts ...Option) (int64, error) {
+	var total int64
+	o := newQueryOptions(opts)
+	err := spec.scope(r.scoped(ctx, o).Model(new(T))).Count(&total).Error
+	if err != nil {
+		return 0, dbGorm.TranslateError(ctx, err, "errorFindResource", r.TableName+" count")
+	}
+	return total, nil
+}
+
+// ExistsCtx reports whether any row matches spec.
+func (r *Repository[T, ID]) ExistsCtx(ctx context.Context, spec Spec, opts ...Option) (bool, error) {
+	var found []int
+	o := newQueryOptions(opts)
+	err := spec.scope(r.scoped(ctx, o).Model(new(T))).Select("1").Limit(1).Find(&found).Error
+	if err != nil {
+		return false, dbGorm.TranslateError(ctx, err, "errorFindResource", r.TableName)
+	}
+	return len(found) > 0, nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
+	"github.com/ginanjar-template-golang/shared-pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// =======================================================
+// MULTI-TENANT SCOPING
+// =======================================================
+
+// DefaultTenantColumn is the conventional tenant column, scoping is opt-in:
+//
+//	noteRepo.TenantColumn = repository.DefaultTenantColumn
+const DefaultTenantColumn = "tenant_id"
+
+type allTenantsKey struct{}
+
+// AllTenants returns a copy of ctx in which repositories skip tenant scoping,
+// e.g. for an admin job running several repositories inside one RunInTx.
+// Per call, use WithAllTenants instead.
+func AllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allTenantsKey{}, true)
+}
+
+func isAllTenants(ctx context.Context) bool {
+	all, _ := ctx.Value(allTenantsKey{}).(bool)
+	return all
+}
+
+// scopedTenantField returns the tenant field when scoping applies to this
+// call, an error when TenantColumn names no field of T.
+func (r *Repository[T, ID]) scopedTenantField(ctx context.Context, o *queryOptions) (*schema.Field, error) {
+	if r.TenantColumn == "" || o.allTenants || isAllTenants(ctx) {
+		return nil, nil
+	}
+
+	sch, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+	field := sch.LookUpField(r.TenantColumn)
+	if field == nil || field.DBName == "" {
+		return nil, fmt.Errorf("%s has no tenant column %q", r.TableName, r.TenantColumn)
+	}
+	return field, nil
+}
+
+// tenant returns the tenant field and the tenant in ctx converted to its type,
+// a nil field when scoping does not apply and Forbidden when ctx has no tenant.
+func (r *Repository[T, ID]) tenant(ctx context.Context, o *queryOptions) (*schema.Field, any, error) {
+	field, err := r.scopedTenantField(ctx, o)
+	if err != nil {
+		return nil, nil, appError.NewCtx(ctx, "forbidden", r.TableName+": invalid tenant column", err)
+	}
+	if field == nil {
+		return nil, nil, nil
+	}
+
+	tenantID := utils.TenantIDFromContext(ctx)
+	if tenantID == "" {
+		return nil, nil, appError.NewCtx(ctx, "forbidden", r.TableName+": no tenant in context", nil)
+	}
+	value, err := tenantValue(field, tenantID)
+	if err != nil {
+		return nil, nil, appError.NewCtx(ctx, "forbidden", fmt.Sprintf("%s: invalid tenant %q", r.TableName, tenantID), err)
+	}
+	return field, value, nil
+}
+
+// tenantScope restricts db to the tenant in ctx. Errors are added to db so
+// they surface from the statement like any query error.
+func (r *Repository[T, ID]) tenantScope(ctx context.Context, o *queryOptions, db *gorm.DB) *gorm.DB {
+	field, value, err := r.tenant(ctx, o)
+	if err != nil {
+		_ = db.AddError(err)
+		return db
+	}
+	if field == nil {
+		return db
+	}
+	return db.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: value})
+}
+
+// stampTenant sets the tenant in ctx on entities without one and rejects
+// entities belonging to another tenant.
+func (r *Repository[T, ID]) stampTenant(ctx context.Context, o *queryOptions, entities ...*T) error {
+	field, value, err := r.tenant(ctx, o)
+	if err != nil || field == nil {
+		return err
+	}
+
+	for _, entity := range entities {
+		rv := reflect.ValueOf(entity).Elem()
+		current := tenantOf(ctx, field, rv)
+		if current == nil {
+			if err := field.Set(ctx, rv, value); err != nil {
+				return appError.NewCtx(ctx, "forbidden", r.TableName+": cannot set tenant", err)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(current, value) {
+			return appError.NewCtx(ctx, "forbidden", r.TableName+": entity belongs to another tenant", nil)
+		}
+	}
+	return nil
+}
+
+// checkTenantFields rejects updates moving rows to another tenant.
+func (r *Repository[T, ID]) checkTenantFields(ctx context.Context, o *queryOptions, fields map[string]any) error {
+	field, _, err := r.tenant(ctx, o)
+	if err != nil || field == nil {
+		return err
+	}
+	for column := range fields {
+		if column == field.DBName || column == field.Name {
+			return appError.NewCtx(ctx, "forbidden", r.TableName+": tenant column cannot be updated", nil)
+		}
+	}
+	return nil
+}
+
+// tenantOf returns the tenant stored in rv, nil when it is unset: the zero
+// value or a nil pointer.
+func tenantOf(ctx context.Context, field *schema.Field, rv reflect.Value) any {
+	v := field.ReflectValueOf(ctx, rv)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.IsZero() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// tenantValue converts the tenant ID to the field type: strings, integers or
+// sql.Scanner types such as uuid.UUID, or pointers to them. Pointer fields get
+// the pointed-to value, which is what queries compare and gorm sets.
+func tenantValue(field *schema.Field, tenantID string) (any, error) {
+	typ := field.FieldType
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	ptr := reflect.New(typ)
+	if scanner, ok := ptr.Interface().(sql.Scanner); ok {
+		if err := scanner.Scan(tenantID); err != nil {
+			return nil, err
+		}
+		return ptr.Elem().Interface(), nil
+	}
+
+	target := ptr.Elem()
+	switch target.Kind() {
+	case reflect.String:
+		target.SetString(tenantID)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(tenantID, 10, typ.Bits())
+		if err != nil {
+			return nil, err
+		}
+		target.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(tenantID, 10, typ.Bits())
+		if err != nil {
+			return nil, err
+		}
+		target.SetUint(n)
+	default:
+		return nil, fmt.Errorf("unsupported tenant field type %s", field.FieldType)
+	}
+	return target.Interface(), nil
+}
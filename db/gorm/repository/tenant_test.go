@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	dbGorm "github.com/ginanjar-template-golang/shared-pkg/db/gorm"
+	"github.com/ginanjar-template-golang/shared-pkg/utils"
+)
+
+type tenantNote struct {
+	ID       uint
+	TenantID string
+	Body     string
+}
+
+func newTenantNotes(t *testing.T) (*BaseRepository[tenantNote], context.Context, context.Context) {
+	t.Helper()
+	repo := NewBaseRepository[tenantNote](openTestDB(t, &tenantNote{}), "tenant_notes")
+	repo.TenantColumn = DefaultTenantColumn
+	return repo, utils.WithTenantID(context.Background(), "a"), utils.WithTenantID(context.Background(), "b")
+}
+
+func TestTenantScoping(t *testing.T) {
+	repo, ctxA, ctxB := newTenantNotes(t)
+
+	noteA, err := repo.CreateCtx(ctxA, &tenantNote{Body: "a1"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if noteA.TenantID != "a" {
+		t.Fatalf("tenant = %q, want a stamped from ctx", noteA.TenantID)
+	}
+	if _, err := repo.CreateCtx(ctxB, &tenantNote{Body: "b1"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	notes, err := repo.FindAllCtx(ctxA)
+	if err != nil {
+		t.Fatalf("find all: %v", err)
+	}
+	if len(notes) != 1 || notes[0].Body != "a1" {
+		t.Fatalf("tenant a sees %+v, want only a1", notes)
+	}
+
+	_, err = repo.FindByIDCtx(ctxB, noteA.ID)
+	assertErrorKey(t, err, dbGorm.KeyRecordNotFound)
+
+	_, err = repo.UpdateFieldsCtx(ctxB, noteA, map[string]any{"body": "hijacked"})
+	assertErrorKey(t, err, dbGorm.KeyRecordNotFound)
+
+	_, err = repo.UpdateFieldsCtx(ctxA, noteA, map[string]any{"tenant_id": "b"})
+	assertErrorKey(t, err, "forbidden")
+
+	err = repo.DeleteCtx(ctxB, noteA.ID)
+	assertErrorKey(t, err, dbGorm.KeyRecordNotFound)
+
+	_, err = repo.CreateCtx(ctxA, &tenantNote{TenantID: "b", Body: "smuggled"})
+	assertErrorKey(t, err, "forbidden")
+
+	all, err := repo.FindAllCtx(ctxA, WithAllTenants())
+	if err != nil {
+		t.Fatalf("find all tenants: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("WithAllTenants sees %d notes, want 2", len(all))
+	}
+	if n, err := repo.CountCtx(AllTenants(context.Background()), Spec{}); err != nil || n != 2 {
+		t.Fatalf("AllTenants count = %d, %v, want 2", n, err)
+	}
+}
+
+func TestTenantScopingRequiresTenant(t *testing.T) {
+	repo, _, _ := newTenantNotes(t)
+
+	_, err := repo.FindAllCtx(context.Background())
+	assertErrorKey(t, err, "forbidden")
+
+	// context-less wrappers have no tenant either
+	_, err = repo.FindAll()
+	assertErrorKey(t, err, "forbidden")
+
+	repo.TenantColumn = "organisation_id"
+	_, err = repo.FindAllCtx(utils.WithTenantID(context.Background(), "a"))
+	assertErrorKey(t, err, "forbidden")
+}
+
+func TestTenantScopingIsOptIn(t *testing.T) {
+	repo := NewBaseRepository[tenantNote](openTestDB(t, &tenantNote{}), "tenant_notes")
+
+	if _, err := repo.Create(&tenantNote{TenantID: "a", Body: "a1"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := repo.CreateCtx(utils.WithTenantID(context.Background(), "b"), &tenantNote{TenantID: "c", Body: "c1"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	notes, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("legacy FindAll: %v", err)
+	}
+	if len(notes) != 2 {
+		t.Fatalf("unscoped repository sees %d notes, want 2", len(notes))
+	}
+}
+
+type pointerTenantNote struct {
+	ID       uint
+	TenantID *string
+	Body     string
+}
+
+type pointerOrgNote struct {
+	ID    uint
+	OrgID *uint
+	Body  string
+}
+
+func TestTenantPointerFields(t *testing.T) {
+	t.Run("string pointer", func(t *testing.T) {
+		repo := NewBaseRepository[pointerTenantNote](openTestDB(t, &pointerTenantNote{}), "pointer_tenant_notes")
+		repo.TenantColumn = DefaultTenantColumn
+		ctxA := utils.WithTenantID(context.Background(), "a")
+		ctxB := utils.WithTenantID(context.Background(), "b")
+
+		// nil is unset and stamped from ctx
+		note, err := repo.CreateCtx(ctxA, &pointerTenantNote{Body: "a1"})
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		if note.TenantID == nil || *note.TenantID != "a" {
+			t.Fatalf("tenant = %v, want a stamped from ctx", note.TenantID)
+		}
+
+		own := "a"
+		if _, err := repo.CreateCtx(ctxA, &pointerTenantNote{TenantID: &own, Body: "a2"}); err != nil {
+			t.Fatalf("create with own tenant: %v", err)
+		}
+		other := "b"
+		_, err = repo.CreateCtx(ctxA, &pointerTenantNote{TenantID: &other, Body: "smuggled"})
+		assertErrorKey(t, err, "forbidden")
+
+		notes, err := repo.FindAllCtx(ctxA)
+		if err != nil || len(notes) != 2 {
+			t.Fatalf("tenant a sees %d notes, %v, want 2", len(notes), err)
+		}
+		_, err = repo.FindByIDCtx(ctxB, note.ID)
+		assertErrorKey(t, err, dbGorm.KeyRecordNotFound)
+	})
+
+	t.Run("uint pointer", func(t *testing.T) {
+		repo := NewBaseRepository[pointerOrgNote](openTestDB(t, &pointerOrgNote{}), "pointer_org_notes")
+		repo.TenantColumn = "org_id"
+		ctx7 := utils.WithTenantID(context.Background(), "7")
+
+		note, err := repo.CreateCtx(ctx7, &pointerOrgNote{Body: "n"})
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		if note.OrgID == nil || *note.OrgID != 7 {
+			t.Fatalf("org = %v, want 7 stamped from ctx", note.OrgID)
+		}
+		if _, err := repo.FindByIDCtx(ctx7, note.ID); err != nil {
+			t.Fatalf("find: %v", err)
+		}
+
+		_, err = repo.FindAllCtx(utils.WithTenantID(context.Background(), "not-a-number"))
+		assertErrorKey(t, err, "forbidden")
+	})
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	dbGorm "github.com/ginanjar-template-golang/shared-pkg/db/gorm"
+	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// =======================================================
+// UPDATE & OPTIMISTIC LOCKING
+// =======================================================
+
+// Versioned lets a model name its optimistic locking column. Without it a field
+// tagged `version:"true"`, or an integer field named Version, is used.
+//
+//	type Product struct {
+//		ID       uint
+//		Revision int64
+//	}
+//	func (Product) VersionColumn() string { return "revision" }
+type Versioned interface {
+	VersionColumn() string
+}
+
+// versionField returns T's optimistic locking field, nil when T has none and
+// an invalidOption AppError when the field is not an integer.
+func (r *Repository[T, ID]) versionField(ctx context.Context, sch *schema.Schema) (*schema.Field, error) {
+	var field *schema.Field
+	if v, ok := any(new(T)).(Versioned); ok {
+		field = sch.LookUpField(v.VersionColumn())
+		if field == nil {
+			return nil, appError.InvalidOptionErrorCtx(ctx, r.TableName, fmt.Sprintf("unknown version column %q", v.VersionColumn()))
+		}
+	} else {
+		for _, f := range sch.Fields {
+			if tagged, _ := f.Tag.Lookup("version"); tagged == "true" && f.DBName != "" {
+				field = f
+				break
+			}
+		}
+	}
+	if field == nil {
+		if f := sch.LookUpField("Version"); f != nil && isInteger(f.FieldType) {
+			return f, nil
+		}
+		return nil, nil
+	}
+
+	if !isInteger(field.FieldType) {
+		return nil, appError.InvalidOptionErrorCtx(ctx, r.TableName, fmt.Sprintf("version field %s must be an integer, got %s", field.Name, field.FieldType))
+	}
+	return field, nil
+}
+
+// UpdateCtx saves every field of entity, or only the WithSelect columns.
+// Versioned models are updated only when the stored version still equals
+// entity's; the version is incremented on success and a versionConflict
+// AppError (409 / Aborted) is returned when another write got there first.
+// Tenant scoped models only update rows of the tenant in ctx.
+func (r *Repository[T, ID]) UpdateCtx(ctx context.Context, entity *T, opts ...Option) (*T, error) {
+	o := newQueryOptions(opts)
+	sch, err := r.schema()
+	if err != nil {
+		return nil, dbGorm.TranslateError(ctx, err, "errorUpdateResource", r.TableName)
+	}
+	if err := r.stampTenant(ctx, o, entity); err != nil {
+		return nil, err
+	}
+
+	// Save inserts when no row matched, so scoped updates go through update
+	version, err := r.versionField(ctx, sch)
+	if err != nil {
+		return nil, err
+	}
+	if version == nil && len(o.selects) == 0 && r.TenantColumn == "" {
+		db := r.conn(ctx)
+		if o.unscoped {
+			db = db.Unscoped()
+		}
+		err := db.Save(entity).Error
+		if err != nil {
+			return nil, dbGorm.TranslateError(ctx, err, "errorUpdateResource", r.TableName)
+		}
+		return entity, nil
+	}
+
+	columns := o.selects
+	if len(columns) == 0 {
+		columns = []string{"*"}
+	} else if version != nil {
+		columns = append(columns, version.DBName)
+	}
+	return entity, r.update(ctx, entity, sch, version, o, func(db *gorm.DB) *gorm.DB {
+		return db.Select(columns).Updates(entity)
+	})
+}
+
+// UpdateFieldsCtx updates only the given columns of the row identified by
+// entity's primary key, e.g. UpdateFieldsCtx(ctx, user, map[string]any{"status": "active"}).
+// entity receives the new values; optimistic locking applies as in UpdateCtx.
+func (r *Repository[T, ID]) UpdateFieldsCtx(ctx context.Context, entity *T, fields map[string]any, opts ...Option) (*T, error) {
+	o := newQueryOptions(opts)
+	sch, err := r.schema()
+	if err != nil {
+		return nil, dbGorm.TranslateError(ctx, err, "errorUpdateResource", r.TableName)
+	}
+	if len(fields) == 0 {
+		return entity, nil
+	}
+	if err := r.checkTenantFields(ctx, o, fields); err != nil {
+		return nil, err
+	}
+
+	version, err := r.versionField(ctx, sch)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]any, len(fields)+1)
+	for column, value := range fields {
+		values[column] = value
+	}
+	return entity, r.update(ctx, entity, sch, version, o, func(db *gorm.DB) *gorm.DB {
+		if version != nil {
+			current, _ := version.ValueOf(ctx, reflect.ValueOf(entity).Elem())
+			values[version.DBName] = current
+		}
+		return db.Updates(values)
+	})
+}
+
+// update runs exec scoped to entity's primary key (and current version),
+// bumping the version first and restoring it when nothing was updated.
+func (r *Repository[T, ID]) update(ctx context.Context, entity *T, sch *schema.Schema, version *schema.Field, o *queryOptions, exec func(db *gorm.DB) *gorm.DB) error {
+	db := r.scoped(ctx, o).Model(entity)
+
+	rv := reflect.ValueOf(entity).Elem()
+	var current int64
+	if version != nil {
+		value := reflect.Indirect(reflect.ValueOf(version.ReflectValueOf(ctx, rv).Interface()))
+		if !value.IsValid() {
+			return appError.InvalidOptionErrorCtx(ctx, r.TableName, "version field "+version.Name+" is nil")
+		}
+		current = value.Convert(reflect.TypeOf(current)).Int()
+		db = db.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: version.DBName}, Value: current})
+		if err := version.Set(ctx, rv, current+1); err != nil {
+			return dbGorm.TranslateError(ctx, err, "errorUpdateResource", r.TableName)
+		}
+	}
+
+	result := exec(db)
+	if result.Error == nil && result.RowsAffected > 0 {
+		return nil
+	}
+	if version != nil {
+		_ = version.Set(ctx, rv, current)
+	}
+	if result.Error != nil {
+		return dbGorm.TranslateError(ctx, result.Error, "errorUpdateResource", r.TableName)
+	}
+
+	// zero rows: the row is gone (or another tenant's), its version moved on,
+	// or nothing changed (MySQL)
+	detail := r.TableName
+	if version != nil {
+		detail = fmt.Sprintf("%s version=%d", r.TableName, current)
+	}
+	var exists int64
+	err := r.scoped(ctx, o).Model(new(T)).Where(primaryKeyExpr(ctx, sch, rv)).Count(&exists).Error
+	if err != nil {
+		return dbGorm.TranslateError(ctx, err, "errorUpdateResource", detail)
+	}
+	if exists == 0 {
+		return appError.NewCtx(ctx, dbGorm.KeyRecordNotFound, detail, nil)
+	}
+	if version == nil {
+		return nil
+	}
+	return appError.NewCtx(ctx, dbGorm.KeyVersionConflict, detail, nil)
+}
+
+// primaryKeyExpr matches the row of the entity held in rv.
+func primaryKeyExpr(ctx context.Context, sch *schema.Schema, rv reflect.Value) clause.Expression {
+	exprs := make([]clause.Expression, 0, len(sch.PrimaryFields))
+	for _, field := range sch.PrimaryFields {
+		value, _ := field.ValueOf(ctx, rv)
+		exprs = append(exprs, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: value})
+	}
+	return clause.And(exprs...)
+}
+
+// isInteger reports whether typ, or the type it points to, is an integer.
+func isInteger(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	dbGorm "github.com/ginanjar-template-golang/shared-pkg/db/gorm"
+)
+
+type versionedItem struct {
+	ID      uint
+	Name    string
+	Version int64
+}
+
+type revisionItem struct {
+	ID   uint
+	Name string
+	Rev  string `version:"true"`
+}
+
+type pointerVersionItem struct {
+	ID      uint
+	Name    string
+	Version *int
+}
+
+func TestUpdateCtxVersionConflict(t *testing.T) {
+	ctx := context.Background()
+	repo := NewBaseRepository[versionedItem](openTestDB(t, &versionedItem{}), "versioned_items")
+
+	item, err := repo.CreateCtx(ctx, &versionedItem{Name: "a"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	stale := *item
+
+	item.Name = "b"
+	if _, err := repo.UpdateCtx(ctx, item); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if item.Version != 1 {
+		t.Fatalf("version = %d, want 1", item.Version)
+	}
+
+	stale.Name = "c"
+	_, err = repo.UpdateCtx(ctx, &stale)
+	assertErrorKey(t, err, dbGorm.KeyVersionConflict)
+	if stale.Version != 0 {
+		t.Fatalf("version after conflict = %d, want 0 restored", stale.Version)
+	}
+
+	_, err = repo.UpdateFieldsCtx(ctx, &stale, map[string]any{"name": "d"})
+	assertErrorKey(t, err, dbGorm.KeyVersionConflict)
+
+	stored, err := repo.FindByIDCtx(ctx, item.ID)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if stored.Name != "b" || stored.Version != 1 {
+		t.Fatalf("stored = %+v, want name b version 1", stored)
+	}
+}
+
+func TestUpdateCtxVersionMissingRow(t *testing.T) {
+	ctx := context.Background()
+	repo := NewBaseRepository[versionedItem](openTestDB(t, &versionedItem{}), "versioned_items")
+
+	_, err := repo.UpdateCtx(ctx, &versionedItem{ID: 42, Name: "x"})
+	assertErrorKey(t, err, dbGorm.KeyRecordNotFound)
+}
+
+func TestUpdateCtxInvalidVersionField(t *testing.T) {
+	ctx := context.Background()
+
+	revisions := NewBaseRepository[revisionItem](openTestDB(t, &revisionItem{}), "revision_items")
+	_, err := revisions.UpdateCtx(ctx, &revisionItem{ID: 1, Name: "x", Rev: "a"})
+	assertErrorKey(t, err, "invalidOption")
+
+	pointers := NewBaseRepository[pointerVersionItem](openTestDB(t, &pointerVersionItem{}), "pointer_version_items")
+	item, err := pointers.CreateCtx(ctx, &pointerVersionItem{Name: "x"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	_, err = pointers.UpdateCtx(ctx, item)
+	assertErrorKey(t, err, "invalidOption")
+
+	item, err = pointers.CreateCtx(ctx, &pointerVersionItem{Name: "y", Version: new(int)})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := pointers.UpdateCtx(ctx, item); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if *item.Version != 1 {
+		t.Fatalf("version = %d, want 1", *item.Version)
+	}
+}
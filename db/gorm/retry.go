@@ -0,0 +1,66 @@
+package gorm
+
+import (
+	"math/rand/v2"
+	"time"
+
+	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
+)
+
+// =======================================================
+// TRANSACTION RETRY
+// =======================================================
+
+// RetryPolicy decides how RunInTx retries a transaction that failed with a
+// retryable error. fn runs again from the start, so keep side effects outside
+// the database (HTTP calls, messages) out of it or make them idempotent.
+type RetryPolicy struct {
+	MaxAttempts int           // including the first one, <= 1 disables retries
+	BaseDelay   time.Duration // backoff before the second attempt, doubled afterwards
+	MaxDelay    time.Duration // backoff cap
+	Retryable   func(err error) bool
+}
+
+// DefaultRetryPolicy is used by NewTransactionManager.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   20 * time.Millisecond,
+	MaxDelay:    500 * time.Millisecond,
+	Retryable:   IsRetryable,
+}
+
+// NoRetry runs the transaction once.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// IsRetryable reports deadlocks, serialization failures and lock timeouts
+// (transactionConflict), whether raw driver errors or AppErrors returned by a repository.
+func IsRetryable(err error) bool {
+	if appErr, ok := appError.FromError(err); ok && appErr.MessageKey == KeyTransactionConflict {
+		return true
+	}
+	return ErrorKey(err) == KeyTransactionConflict
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsRetryable(err)
+}
+
+// backoff returns the delay after the given failed attempt: exponential,
+// capped, with jitter in [delay/2, delay] so competing transactions spread out.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
@@ -0,0 +1,172 @@
+package gorm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	dbGorm "github.com/ginanjar-template-golang/shared-pkg/db/gorm"
+	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
+)
+
+// sqlStateError is a fake driver error carrying a SQLSTATE like pgconn.PgError.
+type sqlStateError struct{ state string }
+
+func (e sqlStateError) Error() string    { return "fake driver error " + e.state }
+func (e sqlStateError) SQLState() string { return e.state }
+
+var errSerialization = sqlStateError{state: "40001"}
+
+func errorKey(err error) string {
+	if appErr, ok := appError.FromError(err); ok {
+		return appErr.MessageKey
+	}
+	return ""
+}
+
+func TestIsRetryable(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"postgres serialization failure", sqlStateError{"40001"}, true},
+		{"postgres deadlock", sqlStateError{"40P01"}, true},
+		{"postgres lock not available", fmt.Errorf("update: %w", sqlStateError{"55P03"}), true},
+		{"postgres unique violation", sqlStateError{"23505"}, false},
+		{"mysql deadlock", errors.New("Error 1213 (40001): Deadlock found when trying to get lock"), true},
+		{"mysql lock wait timeout", errors.New("Error 1205 (HY000): Lock wait timeout exceeded"), false},
+		{"sqlite busy", errors.New("database is locked"), true},
+		{"repository AppError", dbGorm.TranslateError(ctx, sqlStateError{"40P01"}, "errorUpdateResource", "orders"), true},
+		{"other AppError", appError.NewCtx(ctx, "recordNotFound", "orders", nil), false},
+		{"canceled", context.Canceled, false},
+		{"plain error", errors.New("boom"), false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		if got := dbGorm.IsRetryable(tt.err); got != tt.want {
+			t.Errorf("%s: IsRetryable(%v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRunInTxRetry(t *testing.T) {
+	errPlain := errors.New("not retryable")
+	fast := dbGorm.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	tests := []struct {
+		name         string
+		policy       dbGorm.RetryPolicy
+		failures     int   // attempts failing before fn succeeds
+		fail         error // error of the failing attempts
+		wantAttempts int
+		wantKey      string // AppError key of the result, "" for nil or fail itself
+	}{
+		{"succeeds after a conflict", fast, 1, errSerialization, 2, ""},
+		{"retries exhausted", fast, 10, errSerialization, 3, dbGorm.KeyTransactionError},
+		{"not retryable", fast, 10, errPlain, 1, ""},
+		{"NoRetry", dbGorm.NoRetry, 10, errSerialization, 1, ""},
+		{"custom Retryable", dbGorm.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond,
+			Retryable: func(err error) bool { return errors.Is(err, errPlain) }}, 1, errPlain, 2, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &dbGorm.TransactionManager{DB: openTxDB(t), Retry: tt.policy}
+
+			attempts := 0
+			err := tm.RunInTx(context.Background(), func(ctx context.Context) error {
+				attempts++
+				if attempts <= tt.failures {
+					return tt.fail
+				}
+				return nil
+			})
+
+			if attempts != tt.wantAttempts {
+				t.Fatalf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			switch {
+			case tt.wantKey != "":
+				if got := errorKey(err); got != tt.wantKey {
+					t.Fatalf("err = %v, want AppError %s", err, tt.wantKey)
+				}
+			case tt.failures >= tt.wantAttempts:
+				if !errors.Is(err, tt.fail) {
+					t.Fatalf("err = %v, want %v", err, tt.fail)
+				}
+			case err != nil:
+				t.Fatalf("err = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestRunInTxZeroValueManagerDoesNotRetry(t *testing.T) {
+	tm := dbGorm.TransactionManager{DB: openTxDB(t)}
+
+	attempts := 0
+	err := tm.RunInTx(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return errSerialization
+	})
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1", attempts)
+	}
+	if !errors.Is(err, errSerialization) {
+		t.Fatalf("err = %v, want the fn error unchanged", err)
+	}
+}
+
+func TestRunInTxDoesNotRetryNestedSavepoint(t *testing.T) {
+	tm := &dbGorm.TransactionManager{
+		DB:    openTxDB(t),
+		Retry: dbGorm.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond},
+	}
+
+	outer, inner := 0, 0
+	err := tm.RunInTx(context.Background(), func(ctx context.Context) error {
+		outer++
+		innerErr := tm.RunInTx(ctx, func(ctx context.Context) error {
+			inner++
+			return errSerialization
+		})
+		if !errors.Is(innerErr, errSerialization) {
+			t.Errorf("inner err = %v, want the fn error unchanged", innerErr)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("outer: %v", err)
+	}
+	if outer != 1 || inner != 1 {
+		t.Fatalf("outer attempts = %d, inner attempts = %d, want 1 and 1", outer, inner)
+	}
+}
+
+func TestRunInTxRetryHonorsCancel(t *testing.T) {
+	tm := &dbGorm.TransactionManager{
+		DB:    openTxDB(t),
+		Retry: dbGorm.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour, MaxDelay: time.Hour},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	attempts := 0
+	start := time.Now()
+	err := tm.RunInTx(ctx, func(context.Context) error {
+		attempts++
+		cancel() // the caller goes away while the attempt fails
+		return errSerialization
+	})
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("RunInTx took %v, want it to stop waiting on cancel", elapsed)
+	}
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1", attempts)
+	}
+	if got := errorKey(err); got != dbGorm.KeyCanceled {
+		t.Fatalf("err = %v, want AppError %s", err, dbGorm.KeyCanceled)
+	}
+}
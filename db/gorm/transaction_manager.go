@@ -1,46 +1,171 @@
 package gorm
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
 	"github.com/ginanjar-template-golang/shared-pkg/logger"
 	"gorm.io/gorm"
 )
 
 type TransactionManager struct {
-	DB *gorm.DB
+	DB    *gorm.DB
+	Retry RetryPolicy // applied by RunInTx to outermost transactions
 }
 
 func NewTransactionManager(db *gorm.DB) *TransactionManager {
-	return &TransactionManager{DB: db}
+	return &TransactionManager{DB: db, Retry: DefaultRetryPolicy}
 }
 
-// TxFunc callback menerima semua repository yang dibungkus
-type TxFunc func(repos map[string]any) error
+// =======================================================
+// CONTEXT-PROPAGATED TRANSACTIONS
+// =======================================================
 
-// Transaction jalankan callback dalam satu transaksi
-func (tm *TransactionManager) Transaction(
-	fn TxFunc,
-	repoConstructors map[string]func(tx *gorm.DB) any,
-) error {
-	tx := tm.DB.Begin()
-	if tx.Error != nil {
-		return tx.Error
+// TxOption configures a transaction started by RunInTx.
+type TxOption func(*txConfig)
+
+type txConfig struct {
+	sqlOpts *sql.TxOptions
+	retry   RetryPolicy
+}
+
+// WithIsolation sets the isolation level, e.g. sql.LevelSerializable.
+func WithIsolation(level sql.IsolationLevel) TxOption {
+	return func(c *txConfig) {
+		c.sqlOptions().Isolation = level
+	}
+}
+
+// WithReadOnly starts a read-only transaction.
+func WithReadOnly() TxOption {
+	return func(c *txConfig) {
+		c.sqlOptions().ReadOnly = true
+	}
+}
+
+// WithRetry overrides TransactionManager.Retry for one call, e.g. dbGorm.NoRetry.
+func WithRetry(policy RetryPolicy) TxOption {
+	return func(c *txConfig) {
+		c.retry = policy
+	}
+}
+
+func (c *txConfig) sqlOptions() *sql.TxOptions {
+	if c.sqlOpts == nil {
+		c.sqlOpts = &sql.TxOptions{}
+	}
+	return c.sqlOpts
+}
+
+// RunInTx runs fn inside a transaction stored in the ctx it receives, so every
+// context-aware repository called with that ctx joins it. fn's error or a panic
+// rolls back (the panic is re-raised), otherwise the transaction commits.
+// Called again with a ctx that already holds a transaction, RunInTx creates a
+// savepoint instead and opts are ignored.
+//
+// Deadlocks and serialization failures of the outermost transaction are retried
+// according to tm.Retry; once attempts are exhausted a transactionError
+// (DBTransactionError) wrapping the last error is returned.
+//
+//	err := tm.RunInTx(ctx, func(ctx context.Context) error {
+//		if _, err := orderRepo.CreateCtx(ctx, order); err != nil {
+//			return err
+//		}
+//		return stockRepo.DecreaseCtx(ctx, order.ItemID, order.Qty)
+//	}, dbGorm.WithIsolation(sql.LevelRepeatableRead))
+func (tm *TransactionManager) RunInTx(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOption) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cfg := txConfig{retry: tm.Retry}
+	for _, opt := range opts {
+		opt(&cfg)
 	}
 
-	repos := make(map[string]any)
-	for key, constructor := range repoConstructors {
-		repos[key] = constructor(tx)
+	if _, nested := TxFromContext(ctx); nested || cfg.retry.MaxAttempts <= 1 {
+		return tm.runOnce(ctx, fn, cfg.sqlOpts)
 	}
 
-	if err := fn(repos); err != nil {
-		tx.Rollback()
-		logger.Error("Transaction rollback due to error", map[string]any{"error": err})
-		return err
+	log := logger.FromContext(ctx).Component("db")
+	for attempt := 1; ; attempt++ {
+		err := tm.runOnce(ctx, fn, cfg.sqlOpts)
+		if err == nil || !cfg.retry.retryable(err) {
+			return err
+		}
+		if attempt >= cfg.retry.MaxAttempts {
+			return appError.NewCtx(ctx, KeyTransactionError, fmt.Sprintf("retries exhausted after %d attempts", attempt), err)
+		}
+
+		delay := cfg.retry.backoff(attempt)
+		log.Warn("Retrying transaction", map[string]any{
+			"attempt":      attempt,
+			"max_attempts": cfg.retry.MaxAttempts,
+			"delay_ms":     delay.Milliseconds(),
+		}, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return TranslateError(ctx, ctx.Err(), KeyTimeout, "transaction retry")
+		case <-timer.C:
+		}
 	}
+}
 
-	if err := tx.Commit().Error; err != nil {
-		logger.Error("Transaction commit failed", map[string]any{"error": err})
-		return err
+// runOnce runs fn in one transaction (or savepoint when ctx already holds one).
+func (tm *TransactionManager) runOnce(ctx context.Context, fn func(ctx context.Context) error, sqlOpts *sql.TxOptions) error {
+	_, nested := TxFromContext(ctx)
+
+	var fnErr error
+	var txOpts []*sql.TxOptions
+	if sqlOpts != nil {
+		txOpts = append(txOpts, sqlOpts)
+	}
+	err := Conn(ctx, tm.DB).Transaction(func(tx *gorm.DB) error {
+		fnErr = fn(WithTx(ctx, tx))
+		return fnErr
+	}, txOpts...)
+	if err == nil {
+		return nil
 	}
 
-	return nil
+	if fnErr != nil {
+		logger.FromContext(ctx).Component("db").Debug("Transaction rollback due to error", map[string]any{
+			"nested": nested,
+		}, fnErr)
+		return fnErr
+	}
+	logger.FromContext(ctx).Component("db").Error("Transaction failed", map[string]any{
+		"nested": nested,
+	}, err)
+	return TranslateError(ctx, err, KeyTransactionError, "transaction")
+}
+
+// =======================================================
+// LEGACY
+// =======================================================
+
+// TxFunc callback menerima semua repository yang dibungkus
+type TxFunc func(repos map[string]any) error
+
+// Transaction jalankan callback dalam satu transaksi
+//
+// Deprecated: use RunInTx with context-aware repositories.
+func (tm *TransactionManager) Transaction(
+	fn TxFunc,
+	repoConstructors map[string]func(tx *gorm.DB) any,
+) error {
+	return tm.RunInTx(context.Background(), func(ctx context.Context) error {
+		tx, _ := TxFromContext(ctx)
+
+		repos := make(map[string]any)
+		for key, constructor := range repoConstructors {
+			repos[key] = constructor(tx)
+		}
+		return fn(repos)
+	})
 }
@@ -0,0 +1,209 @@
+package gorm_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	dbGorm "github.com/ginanjar-template-golang/shared-pkg/db/gorm"
+	"github.com/ginanjar-template-golang/shared-pkg/db/gorm/repository"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+type txItem struct {
+	ID   uint
+	Name string
+}
+
+// openTxDB opens a file backed sqlite database, so a transaction and reads
+// outside it use separate connections like on a real server.
+func openTxDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "tx.db")), &gorm.Config{Logger: gormLogger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("sql db: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	if err := db.AutoMigrate(&txItem{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func itemNames(t *testing.T, db *gorm.DB) []string {
+	t.Helper()
+	var names []string
+	if err := db.Model(&txItem{}).Order("id").Pluck("name", &names).Error; err != nil {
+		t.Fatalf("pluck: %v", err)
+	}
+	return names
+}
+
+func TestRunInTxNestedSavepoint(t *testing.T) {
+	errInner := errors.New("inner failed")
+	tests := []struct {
+		name  string
+		inner func(ctx context.Context, repo *repository.BaseRepository[txItem]) error
+		want  []string
+	}{
+		{
+			name: "inner commits",
+			inner: func(ctx context.Context, repo *repository.BaseRepository[txItem]) error {
+				_, err := repo.CreateCtx(ctx, &txItem{Name: "inner"})
+				return err
+			},
+			want: []string{"before", "inner", "after"},
+		},
+		{
+			name: "inner error rolls back to the savepoint",
+			inner: func(ctx context.Context, repo *repository.BaseRepository[txItem]) error {
+				if _, err := repo.CreateCtx(ctx, &txItem{Name: "inner"}); err != nil {
+					return err
+				}
+				return errInner
+			},
+			want: []string{"before", "after"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTxDB(t)
+			repo := repository.NewBaseRepository[txItem](db, "tx_items")
+			tm := dbGorm.NewTransactionManager(db)
+
+			err := tm.RunInTx(context.Background(), func(ctx context.Context) error {
+				if _, err := repo.CreateCtx(ctx, &txItem{Name: "before"}); err != nil {
+					return err
+				}
+				innerErr := tm.RunInTx(ctx, func(ctx context.Context) error {
+					return tt.inner(ctx, repo)
+				})
+				if innerErr != nil && !errors.Is(innerErr, errInner) {
+					return innerErr
+				}
+				_, err := repo.CreateCtx(ctx, &txItem{Name: "after"})
+				return err
+			})
+			if err != nil {
+				t.Fatalf("outer: %v", err)
+			}
+			if got := itemNames(t, db); !slices.Equal(got, tt.want) {
+				t.Fatalf("rows = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunInTxNestedPanic(t *testing.T) {
+	db := openTxDB(t)
+	repo := repository.NewBaseRepository[txItem](db, "tx_items")
+	tm := dbGorm.NewTransactionManager(db)
+
+	err := tm.RunInTx(context.Background(), func(ctx context.Context) error {
+		if _, err := repo.CreateCtx(ctx, &txItem{Name: "before"}); err != nil {
+			return err
+		}
+
+		recovered := func() (r any) {
+			defer func() { r = recover() }()
+			_ = tm.RunInTx(ctx, func(ctx context.Context) error {
+				if _, err := repo.CreateCtx(ctx, &txItem{Name: "inner"}); err != nil {
+					return err
+				}
+				panic("inner boom")
+			})
+			return nil
+		}()
+		if recovered != "inner boom" {
+			t.Errorf("recovered %v, want the inner panic re-raised", recovered)
+		}
+
+		_, err := repo.CreateCtx(ctx, &txItem{Name: "after"})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("outer: %v", err)
+	}
+	if got, want := itemNames(t, db), []string{"before", "after"}; !slices.Equal(got, want) {
+		t.Fatalf("rows = %v, want %v", got, want)
+	}
+}
+
+func TestRunInTxOuterErrorRollsBackSavepoints(t *testing.T) {
+	db := openTxDB(t)
+	repo := repository.NewBaseRepository[txItem](db, "tx_items")
+	tm := dbGorm.NewTransactionManager(db)
+	errOuter := errors.New("outer failed")
+
+	err := tm.RunInTx(context.Background(), func(ctx context.Context) error {
+		err := tm.RunInTx(ctx, func(ctx context.Context) error {
+			_, err := repo.CreateCtx(ctx, &txItem{Name: "inner"})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		return errOuter
+	})
+	if !errors.Is(err, errOuter) {
+		t.Fatalf("err = %v, want the outer error", err)
+	}
+	if got := itemNames(t, db); len(got) != 0 {
+		t.Fatalf("rows = %v, want none after the outer rollback", got)
+	}
+}
+
+func TestRunInTxPropagatesToRepositories(t *testing.T) {
+	db := openTxDB(t)
+	repo := repository.NewBaseRepository[txItem](db, "tx_items")
+	tm := dbGorm.NewTransactionManager(db)
+
+	err := tm.RunInTx(context.Background(), func(ctx context.Context) error {
+		tx, ok := dbGorm.TxFromContext(ctx)
+		if !ok || tx == nil {
+			t.Fatal("TxFromContext found no transaction inside RunInTx")
+		}
+		if _, err := repo.CreateCtx(ctx, &txItem{Name: "pending"}); err != nil {
+			return err
+		}
+
+		// the write is visible through the transaction ctx only
+		inTx, err := repo.FindAllCtx(ctx)
+		if err != nil {
+			return err
+		}
+		outside, err := repo.FindAllCtx(context.Background())
+		if err != nil {
+			return err
+		}
+		if len(inTx) != 1 || len(outside) != 0 {
+			t.Errorf("rows in tx = %d, outside = %d, want 1 and 0", len(inTx), len(outside))
+		}
+
+		return tm.RunInTx(ctx, func(nested context.Context) error {
+			if nestedTx, _ := dbGorm.TxFromContext(nested); nestedTx == nil {
+				t.Error("TxFromContext found no transaction inside the savepoint")
+			}
+			rows, err := repo.FindAllCtx(nested)
+			if err == nil && len(rows) != 1 {
+				t.Errorf("rows in savepoint = %d, want 1", len(rows))
+			}
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatalf("RunInTx: %v", err)
+	}
+	if got, want := itemNames(t, db), []string{"pending"}; !slices.Equal(got, want) {
+		t.Fatalf("rows = %v, want %v", got, want)
+	}
+}
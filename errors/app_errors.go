@@ -1,13 +1,10 @@
 package errors
 
 import (
+	"context"
 	"fmt"
 
-	grpcCode "github.com/ginanjar-template-golang/shared-pkg/constants/grpc_code"
-	httpCode "github.com/ginanjar-template-golang/shared-pkg/constants/http_code"
-	internalCode "github.com/ginanjar-template-golang/shared-pkg/constants/internal_code"
-	"github.com/ginanjar-template-golang/shared-pkg/logger"
-	"github.com/ginanjar-template-golang/shared-pkg/translator"
+	"github.com/ginanjar-template-golang/shared-pkg/utils"
 	"google.golang.org/grpc/codes"
 )
 
@@ -15,13 +12,27 @@ import (
 // STRUCT
 // =======================================================
 type AppError struct {
-	HttpCode   int        `json:"http_code"`
-	GrpcCode   codes.Code `json:"grpc_code"`
-	MessageKey string     `json:"message_key"`
-	Data       any        `json:"data,omitempty"`
+	HttpCode     int        `json:"http_code"`
+	GrpcCode     codes.Code `json:"grpc_code"`
+	InternalCode int        `json:"internal_code"` // constants/internal_code
+	MessageKey   string     `json:"message_key"`
+	Data         any        `json:"data,omitempty"`
+	Cause        error      `json:"-"` // logged, never sent to clients
+	Stack        *Stack     `json:"-"` // origin, see SetStackCapture; sent to clients only with SetStackExposure
+}
+
+// StackFrames returns the captured origin, nil when no stack was captured.
+func (e AppError) StackFrames() []utils.StackFrame {
+	if e.Stack == nil {
+		return nil
+	}
+	return *e.Stack
 }
 
 func (e AppError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("[HTTP %d | GRPC %d] %s: %v", e.HttpCode, e.GrpcCode, e.MessageKey, e.Cause)
+	}
 	return fmt.Sprintf("[HTTP %d | GRPC %d] %s", e.HttpCode, e.GrpcCode, e.MessageKey)
 }
 
@@ -29,187 +40,315 @@ func (e AppError) Error() string {
 // RESOURCE ERRORS
 // =======================================================
 func ResourceNotFound(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("notFoundResource"), key)
-	logger.LogMapLevel("warn", internalCode.ResourceNotFound, msg, data)
-	return AppError{HttpCode: httpCode.NotFound, GrpcCode: grpcCode.NotFound, MessageKey: "notFoundResource", Data: data}
+	return ResourceNotFoundCtx(context.Background(), key, data)
+}
+
+func ResourceNotFoundCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "notFoundResource", key, data)
 }
 
 func FindResourceError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("errorFindResource"), key)
-	logger.LogMapLevel("error", internalCode.FailedFindResource, msg, data)
-	return AppError{HttpCode: httpCode.InternalServerError, GrpcCode: grpcCode.Internal, MessageKey: "errorFindResource", Data: data}
+	return FindResourceErrorCtx(context.Background(), key, data)
+}
+
+func FindResourceErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "errorFindResource", key, data)
 }
 
 func CreateResourceError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("errorCreateResource"), key)
-	logger.LogMapLevel("error", internalCode.FailedCreateResource, msg, data)
-	return AppError{HttpCode: httpCode.InternalServerError, GrpcCode: grpcCode.Internal, MessageKey: "errorCreateResource", Data: data}
+	return CreateResourceErrorCtx(context.Background(), key, data)
+}
+
+func CreateResourceErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "errorCreateResource", key, data)
 }
 
 func UpdateResourceError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("errorUpdateResource"), key)
-	logger.LogMapLevel("error", internalCode.FailedUpdateResource, msg, data)
-	return AppError{HttpCode: httpCode.InternalServerError, GrpcCode: grpcCode.Internal, MessageKey: "errorUpdateResource", Data: data}
+	return UpdateResourceErrorCtx(context.Background(), key, data)
+}
+
+func UpdateResourceErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "errorUpdateResource", key, data)
 }
 
 func DeleteResourceError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("errorDeleteResource"), key)
-	logger.LogMapLevel("error", internalCode.FailedDeleteResource, msg, data)
-	return AppError{HttpCode: httpCode.InternalServerError, GrpcCode: grpcCode.Internal, MessageKey: "errorDeleteResource", Data: data}
+	return DeleteResourceErrorCtx(context.Background(), key, data)
+}
+
+func DeleteResourceErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "errorDeleteResource", key, data)
 }
 
 // =======================================================
 // VALIDATION & REQUEST ERRORS
 // =======================================================
 func InvalidBody(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("invalidBody"), key)
-	logger.LogMapLevel("warn", internalCode.InvalidRequestData, msg, data)
-	return AppError{HttpCode: httpCode.BadRequest, GrpcCode: grpcCode.InvalidArgument, MessageKey: "invalidBody", Data: data}
+	return InvalidBodyCtx(context.Background(), key, data)
+}
+
+func InvalidBodyCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "invalidBody", key, data)
 }
 
 func InvalidTypeError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("invalidType"), key)
-	logger.LogMapLevel("warn", internalCode.ValidationError, msg, data)
-	return AppError{HttpCode: httpCode.BadRequest, GrpcCode: grpcCode.InvalidArgument, MessageKey: "invalidType", Data: data}
+	return InvalidTypeErrorCtx(context.Background(), key, data)
+}
+
+func InvalidTypeErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "invalidType", key, data)
 }
 
 func InvalidFormatError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("invalidFormat"), key)
-	logger.LogMapLevel("warn", internalCode.ValidationError, msg, data)
-	return AppError{HttpCode: httpCode.BadRequest, GrpcCode: grpcCode.InvalidArgument, MessageKey: "invalidFormat", Data: data}
+	return InvalidFormatErrorCtx(context.Background(), key, data)
+}
+
+func InvalidFormatErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "invalidFormat", key, data)
 }
 
 func AlreadyUsedError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("alreadyUsed"), key)
-	logger.LogMapLevel("info", internalCode.DuplicateValue, msg, data)
-	return AppError{HttpCode: httpCode.Conflict, GrpcCode: grpcCode.AlreadyExists, MessageKey: "alreadyUsed", Data: data}
+	return AlreadyUsedErrorCtx(context.Background(), key, data)
+}
+
+func AlreadyUsedErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "alreadyUsed", key, data)
 }
 
 func InvalidOptionError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("invalidOption"), key)
-	logger.LogMapLevel("warn", internalCode.OperationFailed, msg, data)
-	return AppError{HttpCode: httpCode.BadRequest, GrpcCode: grpcCode.InvalidArgument, MessageKey: "invalidOption", Data: data}
+	return InvalidOptionErrorCtx(context.Background(), key, data)
+}
+
+func InvalidOptionErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "invalidOption", key, data)
 }
 
 func ValueMissMatch(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("valueMismatch"), key)
-	logger.LogMapLevel("warn", internalCode.OperationFailed, msg, data)
-	return AppError{HttpCode: httpCode.UnprocessableEntity, GrpcCode: grpcCode.FailedPrecondition, MessageKey: "valueMismatch", Data: data}
+	return ValueMissMatchCtx(context.Background(), key, data)
+}
+
+func ValueMissMatchCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "valueMismatch", key, data)
 }
 
 func ValidationFailed(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("validationFailed"), key)
-	logger.LogMapLevel("info", internalCode.ValidationError, msg, data)
-	return AppError{HttpCode: httpCode.BadRequest, GrpcCode: grpcCode.InvalidArgument, MessageKey: "validationFailed", Data: data}
+	return ValidationFailedCtx(context.Background(), key, data)
+}
+
+func ValidationFailedCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "validationFailed", key, data)
 }
 
 // =======================================================
 // AUTHENTICATION & AUTHORIZATION
 // =======================================================
 func Unauthorized(data any) AppError {
-	msg := translator.GetMessageByLang("unauthorized")
-	logger.LogMapLevel("warn", internalCode.AuthInvalidCredentials, msg, data)
-	return AppError{HttpCode: httpCode.Unauthorized, GrpcCode: grpcCode.Unauthenticated, MessageKey: "unauthorized", Data: data}
+	return UnauthorizedCtx(context.Background(), data)
+}
+
+func UnauthorizedCtx(ctx context.Context, data any) AppError {
+	return NewCtx(ctx, "unauthorized", "", data)
 }
 
 func Forbidden(data any) AppError {
-	msg := translator.GetMessageByLang("forbidden")
-	logger.LogMapLevel("warn", internalCode.AuthPermissionDenied, msg, data)
-	return AppError{HttpCode: httpCode.Forbidden, GrpcCode: grpcCode.PermissionDenied, MessageKey: "forbidden", Data: data}
+	return ForbiddenCtx(context.Background(), data)
+}
+
+func ForbiddenCtx(ctx context.Context, data any) AppError {
+	return NewCtx(ctx, "forbidden", "", data)
 }
 
 func InvalidTokenError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("invalidToken"), key)
-	logger.LogMapLevel("warn", internalCode.AuthInvalidToken, msg, data)
-	return AppError{HttpCode: httpCode.Unauthorized, GrpcCode: grpcCode.Unauthenticated, MessageKey: "invalidToken", Data: data}
+	return InvalidTokenErrorCtx(context.Background(), key, data)
+}
+
+func InvalidTokenErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "invalidToken", key, data)
 }
 
 func ExpiredError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("expired"), key)
-	logger.LogMapLevel("info", internalCode.AuthExpiredToken, msg, data)
-	return AppError{HttpCode: httpCode.Unauthorized, GrpcCode: grpcCode.Unauthenticated, MessageKey: "expired", Data: data}
+	return ExpiredErrorCtx(context.Background(), key, data)
+}
+
+func ExpiredErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "expired", key, data)
 }
 
 // =======================================================
 // DATABASE & CACHE ERRORS
 // =======================================================
 func DatabaseError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("databaseError"), key)
-	logger.LogMapLevel("error", internalCode.DBQueryError, msg, data)
-	return AppError{HttpCode: httpCode.InternalServerError, GrpcCode: grpcCode.Internal, MessageKey: "databaseError", Data: data}
+	return DatabaseErrorCtx(context.Background(), key, data)
+}
+
+func DatabaseErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "databaseError", key, data)
 }
 
 func DuplicateKeyError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("duplicateKey"), key)
-	logger.LogMapLevel("info", internalCode.DBDuplicateKey, msg, data)
-	return AppError{HttpCode: httpCode.Conflict, GrpcCode: grpcCode.AlreadyExists, MessageKey: "duplicateKey", Data: data}
+	return DuplicateKeyErrorCtx(context.Background(), key, data)
+}
+
+func DuplicateKeyErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "duplicateKey", key, data)
+}
+
+func RecordNotFoundError(key string, data any) AppError {
+	return RecordNotFoundErrorCtx(context.Background(), key, data)
+}
+
+func RecordNotFoundErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "recordNotFound", key, data)
+}
+
+func ForeignKeyError(key string, data any) AppError {
+	return ForeignKeyErrorCtx(context.Background(), key, data)
+}
+
+func ForeignKeyErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "foreignKeyViolation", key, data)
+}
+
+func CheckViolationError(key string, data any) AppError {
+	return CheckViolationErrorCtx(context.Background(), key, data)
+}
+
+func CheckViolationErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "checkViolation", key, data)
+}
+
+func TransactionError(key string, data any) AppError {
+	return TransactionErrorCtx(context.Background(), key, data)
+}
+
+func TransactionErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "transactionError", key, data)
+}
+
+func TransactionConflictError(key string, data any) AppError {
+	return TransactionConflictErrorCtx(context.Background(), key, data)
+}
+
+func TransactionConflictErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "transactionConflict", key, data)
+}
+
+func DatabaseTimeoutError(key string, data any) AppError {
+	return DatabaseTimeoutErrorCtx(context.Background(), key, data)
+}
+
+func DatabaseTimeoutErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "dbTimeout", key, data)
+}
+
+func DatabaseConnectionError(key string, data any) AppError {
+	return DatabaseConnectionErrorCtx(context.Background(), key, data)
+}
+
+func DatabaseConnectionErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "dbConnectionError", key, data)
+}
+
+func VersionConflictError(key string, data any) AppError {
+	return VersionConflictErrorCtx(context.Background(), key, data)
+}
+
+func VersionConflictErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "versionConflict", key, data)
 }
 
 func CacheError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("cacheError"), key)
-	logger.LogMapLevel("error", internalCode.CacheReadError, msg, data)
-	return AppError{HttpCode: httpCode.ServiceUnavailable, GrpcCode: grpcCode.Unavailable, MessageKey: "cacheError", Data: data}
+	return CacheErrorCtx(context.Background(), key, data)
+}
+
+func CacheErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "cacheError", key, data)
 }
 
 // =======================================================
 // EXTERNAL SERVICE / INTEGRATION
 // =======================================================
 func ExternalAPIError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("externalAPIError"), key)
-	logger.LogMapLevel("error", internalCode.ExternalAPIError, msg, data)
-	return AppError{HttpCode: httpCode.BadGateway, GrpcCode: grpcCode.Unavailable, MessageKey: "externalAPIError", Data: data}
+	return ExternalAPIErrorCtx(context.Background(), key, data)
+}
+
+func ExternalAPIErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "externalAPIError", key, data)
 }
 
 func ExternalTimeoutError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("externalTimeout"), key)
-	logger.LogMapLevel("warn", internalCode.ExternalTimeoutError, msg, data)
-	return AppError{HttpCode: httpCode.GatewayTimeout, GrpcCode: grpcCode.DeadlineExceeded, MessageKey: "externalTimeout", Data: data}
+	return ExternalTimeoutErrorCtx(context.Background(), key, data)
+}
+
+func ExternalTimeoutErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "externalTimeout", key, data)
 }
 
 func ExternalAuthError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("externalAuthError"), key)
-	logger.LogMapLevel("warn", internalCode.ExternalAuthError, msg, data)
-	return AppError{HttpCode: httpCode.Unauthorized, GrpcCode: grpcCode.Unauthenticated, MessageKey: "externalAuthError", Data: data}
+	return ExternalAuthErrorCtx(context.Background(), key, data)
+}
+
+func ExternalAuthErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "externalAuthError", key, data)
 }
 
 func ExternalRateLimitError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("externalRateLimit"), key)
-	logger.LogMapLevel("warn", internalCode.ExternalRateLimit, msg, data)
-	return AppError{HttpCode: httpCode.ServiceUnavailable, GrpcCode: grpcCode.ResourceExhausted, MessageKey: "externalRateLimit", Data: data}
+	return ExternalRateLimitErrorCtx(context.Background(), key, data)
+}
+
+func ExternalRateLimitErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "externalRateLimit", key, data)
 }
 
 // =======================================================
 // FILE & IO
 // =======================================================
 func FileNotFound(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("fileNotFound"), key)
-	logger.LogMapLevel("warn", internalCode.FileNotFoundError, msg, data)
-	return AppError{HttpCode: httpCode.NotFound, GrpcCode: grpcCode.NotFound, MessageKey: "fileNotFound", Data: data}
+	return FileNotFoundCtx(context.Background(), key, data)
+}
+
+func FileNotFoundCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "fileNotFound", key, data)
 }
 
 func FileReadError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("fileReadError"), key)
-	logger.LogMapLevel("error", internalCode.FileReadError, msg, data)
-	return AppError{HttpCode: httpCode.InternalServerError, GrpcCode: grpcCode.Internal, MessageKey: "fileReadError", Data: data}
+	return FileReadErrorCtx(context.Background(), key, data)
+}
+
+func FileReadErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "fileReadError", key, data)
 }
 
 // =======================================================
 // TIMEOUT / GENERAL
 // =======================================================
 func TimeoutError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("timeoutError"), key)
-	logger.LogMapLevel("warn", internalCode.TimeoutError, msg, data)
-	return AppError{HttpCode: httpCode.GatewayTimeout, GrpcCode: grpcCode.DeadlineExceeded, MessageKey: "timeoutError", Data: data}
+	return TimeoutErrorCtx(context.Background(), key, data)
+}
+
+func TimeoutErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "timeoutError", key, data)
+}
+
+func RequestCanceledError(key string, data any) AppError {
+	return RequestCanceledErrorCtx(context.Background(), key, data)
+}
+
+func RequestCanceledErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "requestCanceled", key, data)
 }
 
 func GeneralError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("generalRequestErrors"), key)
-	logger.LogMapLevel("error", internalCode.OperationFailed, msg, data)
-	return AppError{HttpCode: httpCode.InternalServerError, GrpcCode: grpcCode.Internal, MessageKey: "generalRequestErrors", Data: data}
+	return GeneralErrorCtx(context.Background(), key, data)
+}
+
+func GeneralErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "generalRequestErrors", key, data)
 }
 
 func UnknownError(key string, data any) AppError {
-	msg := fmt.Sprintf("%s: %s", translator.GetMessageByLang("somethingWentWrong"), key)
-	logger.LogMapLevel("error", internalCode.UnknownError, msg, data)
-	return AppError{HttpCode: httpCode.InternalServerError, GrpcCode: grpcCode.Unknown, MessageKey: "somethingWentWrong", Data: data}
+	return UnknownErrorCtx(context.Background(), key, data)
+}
+
+func UnknownErrorCtx(ctx context.Context, key string, data any) AppError {
+	return NewCtx(ctx, "somethingWentWrong", key, data)
 }
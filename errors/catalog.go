@@ -0,0 +1,165 @@
+package errors
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ginanjar-template-golang/shared-pkg/logger"
+	"github.com/ginanjar-template-golang/shared-pkg/translator"
+	"github.com/ginanjar-template-golang/shared-pkg/utils"
+	"google.golang.org/grpc/codes"
+)
+
+// =======================================================
+// ERROR CATALOG
+// =======================================================
+
+// ErrorSpec describes how an error key maps to HTTP, gRPC and internal codes.
+// The key doubles as the translator message key.
+type ErrorSpec struct {
+	Key          string     `json:"key"`
+	HttpCode     int        `json:"http_code"`
+	GrpcCode     codes.Code `json:"grpc_code"`     // number or name, e.g. "NOT_FOUND"
+	InternalCode int        `json:"internal_code"` // constants/internal_code
+	LogLevel     string     `json:"log_level"`     // debug | info | warn | error
+}
+
+// fallbackKey is used when New is called with a key that was never defined
+const fallbackKey = "somethingWentWrong"
+
+//go:embed catalog.json
+var builtinCatalogJSON []byte
+
+var (
+	catalog   = map[string]ErrorSpec{}
+	catalogMu sync.RWMutex
+)
+
+func init() {
+	if err := LoadCatalogJSON(builtinCatalogJSON); err != nil {
+		panic(fmt.Sprintf("errors: invalid built-in catalog: %v", err))
+	}
+}
+
+// Define registers (or overrides) the spec for key. Missing fields default to
+// HTTP 500, gRPC Internal and log level "error".
+//
+//	errors.Define("insufficientBalance", errors.ErrorSpec{
+//		HttpCode:     httpCode.UnprocessableEntity,
+//		GrpcCode:     grpcCode.FailedPrecondition,
+//		InternalCode: internalCode.InsufficientBalance,
+//		LogLevel:     "warn",
+//	})
+func Define(key string, spec ErrorSpec) {
+	spec.Key = key
+	if spec.HttpCode == 0 {
+		spec.HttpCode = 500
+	}
+	if spec.GrpcCode == codes.OK {
+		spec.GrpcCode = codes.Internal
+	}
+	switch strings.ToLower(spec.LogLevel) {
+	case "debug", "info", "warn", "error":
+		spec.LogLevel = strings.ToLower(spec.LogLevel)
+	default:
+		spec.LogLevel = "error"
+	}
+
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	catalog[key] = spec
+}
+
+// LoadCatalogJSON defines every entry of a JSON object keyed by error key,
+// typically a file embedded by the service:
+//
+//	{"paymentFailed": {"http_code": 402, "grpc_code": "FAILED_PRECONDITION", "internal_code": 1603, "log_level": "error"}}
+func LoadCatalogJSON(data []byte) error {
+	var specs map[string]ErrorSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		return fmt.Errorf("errors: parse catalog: %w", err)
+	}
+	for key, spec := range specs {
+		Define(key, spec)
+	}
+	return nil
+}
+
+// Lookup returns the spec registered for key.
+func Lookup(key string) (ErrorSpec, bool) {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	spec, ok := catalog[key]
+	return spec, ok
+}
+
+// Catalog lists every registered spec ordered by internal code, e.g. for API docs.
+func Catalog() []ErrorSpec {
+	catalogMu.RLock()
+	out := make([]ErrorSpec, 0, len(catalog))
+	for _, spec := range catalog {
+		out = append(out, spec)
+	}
+	catalogMu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].InternalCode != out[j].InternalCode {
+			return out[i].InternalCode < out[j].InternalCode
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// =======================================================
+// CONSTRUCTORS
+// =======================================================
+
+// New builds the AppError registered under key, see NewCtx.
+func New(key, detail string, data any) AppError {
+	return NewCtx(context.Background(), key, detail, data)
+}
+
+// NewCtx logs and builds the AppError registered under key.
+// detail is appended to the log message only, e.g. the resource name.
+// Log messages are always written in English regardless of the request language.
+// An error passed as data becomes the Cause so it is never serialized to clients.
+func NewCtx(ctx context.Context, key, detail string, data any) AppError {
+	spec, ok := Lookup(key)
+	if !ok {
+		spec, _ = Lookup(fallbackKey)
+		detail = strings.TrimSpace(fmt.Sprintf("undefined error key %q %s", key, detail))
+	}
+
+	var cause error
+	if err, isErr := data.(error); isErr {
+		cause, data = err, nil
+	}
+
+	msg := translator.GetMessageByLang(spec.Key)
+	if detail != "" {
+		msg = fmt.Sprintf("%s: %s", msg, detail)
+	}
+
+	var stack []utils.StackFrame
+	if spec.LogLevel == "error" && captureStack.Load() {
+		stack = callerStack()
+		ctx = logger.WithContext(ctx, logger.FromContext(ctx).With("stack", stack))
+	}
+	logger.LogMapLevelCtx(ctx, spec.LogLevel, spec.InternalCode, msg, data, cause)
+
+	return AppError{
+		HttpCode:     spec.HttpCode,
+		GrpcCode:     spec.GrpcCode,
+		InternalCode: spec.InternalCode,
+		MessageKey:   spec.Key,
+		Data:         data,
+		Cause:        cause,
+		Stack:        newStack(stack),
+	}
+}
@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"encoding/json"
+	"strconv"
+
+	httpCode "github.com/ginanjar-template-golang/shared-pkg/constants/http_code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// =======================================================
+// GRPC STATUS -> APPERROR (CLIENT SIDE)
+// =======================================================
+
+// catalog keys used when a downstream status carries no ErrorInfo
+var grpcCodeKeys = map[codes.Code]string{
+	codes.InvalidArgument:    "invalidRequest",
+	codes.NotFound:           "notFoundResource",
+	codes.AlreadyExists:      "alreadyUsed",
+	codes.PermissionDenied:   "forbidden",
+	codes.Unauthenticated:    "unauthorized",
+	codes.FailedPrecondition: "invalidState",
+	codes.ResourceExhausted:  "externalRateLimit",
+	codes.DeadlineExceeded:   "externalTimeout",
+	codes.Unavailable:        "externalAPIError",
+	codes.Canceled:           "requestCanceled",
+}
+
+// FromGRPCStatus rebuilds the AppError sent by grpc_response.FromAppError on
+// another service, so it can be passed straight to http_response.FromAppError.
+// Message key, internal code, HTTP code and data come from the ErrorInfo detail,
+// BadRequest violations become the field error map. Statuses without details are
+// mapped by gRPC code. The original status error is kept as Cause.
+// Returns false when err is nil or not a gRPC status.
+func FromGRPCStatus(err error) (AppError, bool) {
+	if err == nil {
+		return AppError{}, false
+	}
+	if appErr, ok := FromError(err); ok {
+		return appErr, true
+	}
+
+	st, ok := status.FromError(err)
+	if !ok || st.Code() == codes.OK {
+		return AppError{}, false
+	}
+
+	appErr := AppError{GrpcCode: st.Code(), Cause: err}
+
+	var info *errdetails.ErrorInfo
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			info = d
+		case *errdetails.BadRequest:
+			fields := make(map[string]string, len(d.GetFieldViolations()))
+			for _, v := range d.GetFieldViolations() {
+				fields[v.GetField()] = v.GetDescription()
+			}
+			appErr.Data = fields
+		}
+	}
+
+	if info == nil {
+		key, found := grpcCodeKeys[st.Code()]
+		if !found {
+			key = "externalAPIError"
+		}
+		spec, _ := Lookup(key)
+		appErr.MessageKey = spec.Key
+		appErr.HttpCode = spec.HttpCode
+		appErr.InternalCode = spec.InternalCode
+		return appErr, true
+	}
+
+	appErr.MessageKey = info.GetReason()
+	md := info.GetMetadata()
+	if key := md["message_key"]; key != "" {
+		appErr.MessageKey = key
+	}
+	spec, known := Lookup(appErr.MessageKey)
+
+	appErr.HttpCode, _ = strconv.Atoi(md["http_code"])
+	if appErr.HttpCode == 0 {
+		if known {
+			appErr.HttpCode = spec.HttpCode
+		} else {
+			appErr.HttpCode = httpCodeFromGrpc(st.Code())
+		}
+	}
+	appErr.InternalCode, _ = strconv.Atoi(md["internal_code"])
+	if appErr.InternalCode == 0 && known {
+		appErr.InternalCode = spec.InternalCode
+	}
+	if raw := md["data"]; raw != "" && appErr.Data == nil {
+		var data any
+		if json.Unmarshal([]byte(raw), &data) == nil {
+			appErr.Data = data
+		}
+	}
+
+	return appErr, true
+}
+
+func httpCodeFromGrpc(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return httpCode.BadRequest
+	case codes.Unauthenticated:
+		return httpCode.Unauthorized
+	case codes.PermissionDenied:
+		return httpCode.Forbidden
+	case codes.NotFound:
+		return httpCode.NotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return httpCode.Conflict
+	case codes.FailedPrecondition:
+		return httpCode.UnprocessableEntity
+	case codes.ResourceExhausted:
+		return httpCode.TooManyRequests
+	case codes.Canceled:
+		return httpCode.ClientClosedRequest
+	case codes.DeadlineExceeded:
+		return httpCode.GatewayTimeout
+	case codes.Unimplemented:
+		return httpCode.NotImplemented
+	case codes.Unavailable:
+		return httpCode.ServiceUnavailable
+	default:
+		return httpCode.InternalServerError
+	}
+}
@@ -0,0 +1,115 @@
+package errors_test
+
+import (
+	"context"
+	stdErrors "errors"
+	"reflect"
+	"testing"
+
+	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
+	grpcResponse "github.com/ginanjar-template-golang/shared-pkg/response/grpc_response"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFromGRPCStatusRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		err  appError.AppError
+		data any
+	}{
+		{"plain", appError.ForbiddenCtx(ctx, nil), nil},
+		{"field violations", appError.NewCtx(ctx, "validationFailed", "", map[string]string{"email": "Email is required"}), map[string]string{"email": "Email is required"}},
+		{"json data", appError.NewCtx(ctx, "recordNotFound", "users", map[string]any{"id": 7}), map[string]any{"id": float64(7)}},
+		{"canceled", appError.RequestCanceledErrorCtx(ctx, "users", nil), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wire := grpcResponse.FromAppError(ctx, tt.err)
+
+			got, ok := appError.FromGRPCStatus(wire)
+			if !ok {
+				t.Fatalf("FromGRPCStatus(%v) = false", wire)
+			}
+			if got.MessageKey != tt.err.MessageKey || got.HttpCode != tt.err.HttpCode ||
+				got.GrpcCode != tt.err.GrpcCode || got.InternalCode != tt.err.InternalCode {
+				t.Fatalf("got %s %d/%s/%d, want %s %d/%s/%d",
+					got.MessageKey, got.HttpCode, got.GrpcCode, got.InternalCode,
+					tt.err.MessageKey, tt.err.HttpCode, tt.err.GrpcCode, tt.err.InternalCode)
+			}
+			if !reflect.DeepEqual(got.Data, tt.data) {
+				t.Fatalf("data = %#v, want %#v", got.Data, tt.data)
+			}
+			if got.Cause != wire {
+				t.Fatalf("cause = %v, want the status error", got.Cause)
+			}
+		})
+	}
+}
+
+func TestFromGRPCStatusWithoutDetails(t *testing.T) {
+	tests := []struct {
+		code     codes.Code
+		wantKey  string
+		wantHTTP int
+	}{
+		{codes.NotFound, "notFoundResource", 404},
+		{codes.PermissionDenied, "forbidden", 403},
+		{codes.DeadlineExceeded, "externalTimeout", 504},
+		{codes.Internal, "externalAPIError", 502},
+		{codes.Canceled, "requestCanceled", 499},
+	}
+	for _, tt := range tests {
+		got, ok := appError.FromGRPCStatus(status.Error(tt.code, "downstream"))
+		if !ok {
+			t.Fatalf("%s: FromGRPCStatus = false", tt.code)
+		}
+		if got.MessageKey != tt.wantKey || got.HttpCode != tt.wantHTTP || got.GrpcCode != tt.code {
+			t.Errorf("%s: got %s %d/%s, want %s %d/%s", tt.code, got.MessageKey, got.HttpCode, got.GrpcCode, tt.wantKey, tt.wantHTTP, tt.code)
+		}
+	}
+}
+
+func TestFromGRPCStatusUnknownReason(t *testing.T) {
+	tests := []struct {
+		code     codes.Code
+		wantHTTP int
+	}{
+		{codes.Canceled, 499},
+		{codes.NotFound, 404},
+		{codes.Internal, 500},
+	}
+	for _, tt := range tests {
+		st, err := status.New(tt.code, "downstream").WithDetails(&errdetails.ErrorInfo{Reason: "otherServiceKey"})
+		if err != nil {
+			t.Fatalf("with details: %v", err)
+		}
+		got, ok := appError.FromGRPCStatus(st.Err())
+		if !ok {
+			t.Fatalf("%s: FromGRPCStatus = false", tt.code)
+		}
+		if got.MessageKey != "otherServiceKey" || got.HttpCode != tt.wantHTTP {
+			t.Errorf("%s: got %s %d, want otherServiceKey %d", tt.code, got.MessageKey, got.HttpCode, tt.wantHTTP)
+		}
+	}
+}
+
+func TestFromGRPCStatusNotAStatus(t *testing.T) {
+	for name, err := range map[string]error{
+		"nil":       nil,
+		"plain":     stdErrors.New("boom"),
+		"status OK": status.Error(codes.OK, ""),
+	} {
+		if _, ok := appError.FromGRPCStatus(err); ok {
+			t.Errorf("%s: FromGRPCStatus = true, want false", name)
+		}
+	}
+
+	original := appError.ForbiddenCtx(context.Background(), nil)
+	got, ok := appError.FromGRPCStatus(original)
+	if !ok || got.MessageKey != original.MessageKey {
+		t.Fatalf("AppError passed through as %+v, %v", got, ok)
+	}
+}
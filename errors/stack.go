@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ginanjar-template-golang/shared-pkg/logger"
+	"github.com/ginanjar-template-golang/shared-pkg/utils"
+)
+
+// =======================================================
+// STACK TRACES
+// =======================================================
+
+const packagePrefix = "github.com/ginanjar-template-golang/shared-pkg/errors."
+
+// Stack is a captured call stack. AppError holds it by pointer so AppError
+// values stay comparable with ==.
+type Stack []utils.StackFrame
+
+var captureStack atomic.Bool
+
+// SetStackCapture turns stack capture on or off for AppErrors whose spec logs at
+// "error" level. Warn/info errors (not found, validation, ...) are hot paths and
+// never capture a stack. Off by default.
+func SetStackCapture(enabled bool) {
+	captureStack.Store(enabled)
+}
+
+var exposeStack atomic.Bool
+
+// SetStackExposure sends captured stacks to clients: "stack" of the HTTP error
+// and problem responses, gRPC DebugInfo. Frames reveal file paths and function
+// names, so it is off by default and independent of the logger environment;
+// enable it on local / dev deployments only.
+func SetStackExposure(enabled bool) {
+	exposeStack.Store(enabled)
+}
+
+// StackExposed reports whether stacks are sent to clients, see SetStackExposure.
+func StackExposed() bool {
+	return exposeStack.Load()
+}
+
+// callerStack captures the stack starting at the first frame outside this package.
+func callerStack() []utils.StackFrame {
+	frames := utils.CaptureStack(1)
+	for i, frame := range frames {
+		if !strings.HasPrefix(frame.Function, packagePrefix) {
+			return frames[i:]
+		}
+	}
+	return frames
+}
+
+// newStack returns frames as a *Stack, nil when there are none.
+func newStack(frames []utils.StackFrame) *Stack {
+	if len(frames) == 0 {
+		return nil
+	}
+	stack := Stack(frames)
+	return &stack
+}
+
+// FromPanicCtx logs a recovered panic once with its stack and returns it as an
+// UnknownError. The panic value is kept as Cause, never sent to clients.
+// Call it from the deferred recover with stack = utils.CaptureStack(1);
+// keyValues are extra log fields, e.g. "method", info.FullMethod.
+func FromPanicCtx(ctx context.Context, recovered any, stack []utils.StackFrame, keyValues ...any) AppError {
+	spec, _ := Lookup(fallbackKey)
+
+	cause, ok := recovered.(error)
+	if !ok {
+		cause = fmt.Errorf("%v", recovered)
+	}
+
+	l := logger.FromContext(ctx).With(keyValues...).With("stack", stack, "panic", fmt.Sprintf("%v", recovered))
+	logger.LogMapLevelCtx(logger.WithContext(ctx, l), spec.LogLevel, spec.InternalCode, "Panic recovered", nil, cause)
+
+	return AppError{
+		HttpCode:     spec.HttpCode,
+		GrpcCode:     spec.GrpcCode,
+		InternalCode: spec.InternalCode,
+		MessageKey:   spec.Key,
+		Cause:        fmt.Errorf("panic: %w", cause),
+		Stack:        newStack(stack),
+	}
+}
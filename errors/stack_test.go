@@ -0,0 +1,31 @@
+package errors_test
+
+import (
+	"context"
+	stdErrors "errors"
+	"testing"
+
+	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
+)
+
+func TestAppErrorComparableWithStack(t *testing.T) {
+	appError.SetStackCapture(true)
+	defer appError.SetStackCapture(false)
+
+	err := appError.NewCtx(context.Background(), "unknownError", "", nil)
+	if len(err.StackFrames()) == 0 {
+		t.Fatal("no stack captured for an error level AppError")
+	}
+
+	// == and errors.Is on the AppError value itself must not panic
+	same := err
+	if same != err {
+		t.Fatal("copy of an AppError is not equal to it")
+	}
+	if !stdErrors.Is(error(err), same) {
+		t.Fatal("errors.Is(err, copy) = false")
+	}
+	if other := appError.NewCtx(context.Background(), "unknownError", "", nil); other == err {
+		t.Fatal("AppErrors with distinct stacks compare equal")
+	}
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	stdErrors "errors"
+)
+
+// =======================================================
+// CAUSE CHAIN
+// =======================================================
+
+// Unwrap returns the underlying cause so errors.Is / errors.As walk into it,
+// e.g. errors.Is(err, gorm.ErrRecordNotFound).
+func (e AppError) Unwrap() error {
+	return e.Cause
+}
+
+// Is matches another AppError (value or pointer) by message key and/or internal code,
+// whichever the target sets, e.g.
+// errors.Is(err, errors.AppError{InternalCode: internalCode.AuthExpiredToken}).
+func (e AppError) Is(target error) bool {
+	var t AppError
+	switch v := target.(type) {
+	case AppError:
+		t = v
+	case *AppError:
+		if v == nil {
+			return false
+		}
+		t = *v
+	default:
+		return false
+	}
+
+	if t.MessageKey == "" && t.InternalCode == 0 {
+		return false
+	}
+	if t.MessageKey != "" && t.MessageKey != e.MessageKey {
+		return false
+	}
+	if t.InternalCode != 0 && t.InternalCode != e.InternalCode {
+		return false
+	}
+	return true
+}
+
+// As lets errors.As fill both an AppError and an *AppError target,
+// whichever form was returned.
+func (e AppError) As(target any) bool {
+	switch t := target.(type) {
+	case *AppError:
+		*t = e
+		return true
+	case **AppError:
+		copied := e
+		*t = &copied
+		return true
+	}
+	return false
+}
+
+// WithCause returns a copy of e wrapping err. The cause is not logged again.
+func (e AppError) WithCause(err error) AppError {
+	e.Cause = err
+	return e
+}
+
+// FromError finds the first AppError (value or pointer) in err's chain.
+func FromError(err error) (AppError, bool) {
+	var appErr AppError
+	if err == nil || !stdErrors.As(err, &appErr) {
+		return AppError{}, false
+	}
+	return appErr, true
+}
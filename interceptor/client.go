@@ -0,0 +1,44 @@
+package interceptor
+
+import (
+	"context"
+
+	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
+	"github.com/ginanjar-template-golang/shared-pkg/logger"
+	"google.golang.org/grpc"
+)
+
+// UnaryClientErrorDecoder turns error statuses returned by downstream services into
+// AppErrors (see errors.FromGRPCStatus), so handlers can pass them straight to
+// http_response.FromAppError or grpc_response.FromAppError.
+//
+//	grpc.NewClient(addr, grpc.WithChainUnaryInterceptor(interceptor.UnaryClientErrorDecoder()))
+func UnaryClientErrorDecoder() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil {
+			return nil
+		}
+
+		appErr, ok := appError.FromGRPCStatus(err)
+		if !ok {
+			return err
+		}
+
+		logger.WarnCtx(ctx, "gRPC downstream call failed", map[string]any{
+			"target":        cc.Target(),
+			"method":        method,
+			"message_key":   appErr.MessageKey,
+			"internal_code": appErr.InternalCode,
+		}, err)
+
+		return appErr
+	}
+}
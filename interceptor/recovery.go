@@ -2,10 +2,8 @@ package interceptor
 
 import (
 	"context"
-	"fmt"
 
 	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
-	"github.com/ginanjar-template-golang/shared-pkg/logger"
 	grpcResponse "github.com/ginanjar-template-golang/shared-pkg/response/grpc_response"
 	"github.com/ginanjar-template-golang/shared-pkg/utils"
 	"google.golang.org/grpc"
@@ -18,18 +16,13 @@ func UnaryRecovery() grpc.UnaryServerInterceptor {
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (resp interface{}, err error) {
+		ctx = withRequestContext(ctx)
+
 		defer func() {
 			if r := recover(); r != nil {
-				reqID := utils.GetRequestID()
-
-				logger.Error("🔥 Panic recovered in gRPC", map[string]any{
-					"request_id": reqID,
-					"panic":      r,
-					"method":     info.FullMethod,
-				})
-
 				// Bungkus panic jadi AppError agar seragam
-				appErr := appError.UnknownError("panicRecovered", fmt.Sprintf("%v", r))
+				stack := utils.CaptureStack(1)
+				appErr := appError.FromPanicCtx(ctx, r, stack, "method", info.FullMethod)
 
 				err = grpcResponse.FromAppError(ctx, appErr)
 			}
@@ -9,7 +9,6 @@ import (
 	"google.golang.org/grpc/metadata"
 
 	"github.com/ginanjar-template-golang/shared-pkg/logger"
-	"github.com/ginanjar-template-golang/shared-pkg/translator"
 	"google.golang.org/grpc"
 )
 
@@ -36,6 +35,20 @@ func ExtractMetadata(ctx context.Context) (requestID, lang string) {
 	return requestID, lang
 }
 
+// withRequestContext attaches the request-scoped values from incoming metadata to ctx.
+// An existing RequestContext (set by an earlier interceptor) is kept as is.
+func withRequestContext(ctx context.Context) context.Context {
+	if utils.GetRequestContext(ctx) != nil {
+		return ctx
+	}
+	reqID, lang := ExtractMetadata(ctx)
+	return utils.WithRequestContext(ctx, &utils.RequestContext{
+		RequestID: reqID,
+		Lang:      lang,
+		StartTime: time.Now(),
+	})
+}
+
 func UnaryRequestLogger() grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
@@ -45,22 +58,21 @@ func UnaryRequestLogger() grpc.UnaryServerInterceptor {
 	) (resp any, err error) {
 		start := time.Now()
 
-		reqID, lang := ExtractMetadata(ctx)
-		utils.SetRequestID(reqID)
-		translator.InitGlobalTranslator(lang)
+		ctx = withRequestContext(ctx)
+		ctx = logger.WithContext(ctx, logger.FromContext(ctx).Component("grpc").With(
+			"request_id", utils.RequestIDFromContext(ctx),
+			"method", info.FullMethod,
+		))
 
-		logger.Trace("gRPC Request Start", map[string]any{
-			"request_id": reqID,
-			"method":     info.FullMethod,
-			"request":    req,
+		logger.TraceCtx(ctx, "gRPC Request Start", map[string]any{
+			"request": req,
 		})
 
 		resp, err = handler(ctx, req)
 
 		duration := time.Since(start)
 
-		logger.Trace("gRPC Request End", map[string]any{
-			"request_id":  reqID,
+		logger.TraceCtx(ctx, "gRPC Request End", map[string]any{
 			"duration_ms": duration.Milliseconds(),
 			"error":       err,
 		})
@@ -0,0 +1,80 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
+	"github.com/ginanjar-template-golang/shared-pkg/logger"
+	grpcResponse "github.com/ginanjar-template-golang/shared-pkg/response/grpc_response"
+	"github.com/ginanjar-template-golang/shared-pkg/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type TenantConfig struct {
+	Metadata string // default "x-tenant-id"
+	Claim    string // user claim, default "tenant_id"
+	Required bool   // reject calls without a tenant (PermissionDenied)
+
+	// TrustMetadata takes the tenant from Metadata when the claims have none.
+	// Metadata is client controlled, enable it only for internal callers
+	// (service to service) that set it after authenticating the user.
+	TrustMetadata bool
+}
+
+// UnaryTenant stores the caller's tenant ID, taken from the user claims
+// (utils.WithUserClaims, set by an auth interceptor), in the request context
+// like middleware.Tenant. Metadata naming another tenant is rejected with
+// PermissionDenied. Chain it after UnaryRequestLogger and the auth interceptor.
+func UnaryTenant(cfg TenantConfig) grpc.UnaryServerInterceptor {
+	if cfg.Metadata == "" {
+		cfg.Metadata = "x-tenant-id"
+	}
+	if cfg.Claim == "" {
+		cfg.Claim = "tenant_id"
+	}
+	key := strings.ToLower(cfg.Metadata)
+
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		ctx = withRequestContext(ctx)
+		claimed := utils.TenantIDFromClaims(utils.UserClaimsFromContext(ctx), cfg.Claim)
+
+		requested := ""
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if vals := md.Get(key); len(vals) > 0 {
+				requested = vals[0]
+			}
+		}
+
+		if claimed != "" && requested != "" && claimed != requested {
+			logger.WarnCtx(ctx, "Tenant metadata does not match token", map[string]any{
+				"method": info.FullMethod,
+			})
+			return nil, grpcResponse.FromAppError(ctx, appError.ForbiddenCtx(ctx, nil))
+		}
+
+		tenantID := claimed
+		if tenantID == "" && cfg.TrustMetadata {
+			tenantID = requested
+		}
+		if tenantID == "" {
+			if cfg.Required {
+				logger.WarnCtx(ctx, "Missing tenant", map[string]any{
+					"method": info.FullMethod,
+				})
+				return nil, grpcResponse.FromAppError(ctx, appError.ForbiddenCtx(ctx, nil))
+			}
+			return handler(ctx, req)
+		}
+
+		ctx = utils.WithTenantID(ctx, tenantID)
+		ctx = logger.WithContext(ctx, logger.FromContext(ctx).With("tenant_id", tenantID))
+		return handler(ctx, req)
+	}
+}
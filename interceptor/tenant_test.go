@@ -0,0 +1,56 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ginanjar-template-golang/shared-pkg/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryTenant(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        TenantConfig
+		claim      string
+		metadata   string
+		wantCode   codes.Code
+		wantTenant string
+	}{
+		{"claim", TenantConfig{Required: true}, "a", "", codes.OK, "a"},
+		{"metadata naming another tenant", TenantConfig{}, "a", "b", codes.PermissionDenied, ""},
+		{"untrusted metadata is ignored", TenantConfig{}, "", "b", codes.OK, ""},
+		{"untrusted metadata does not satisfy Required", TenantConfig{Required: true}, "", "b", codes.PermissionDenied, ""},
+		{"trusted metadata", TenantConfig{Required: true, TrustMetadata: true}, "", "b", codes.OK, "b"},
+		{"missing tenant", TenantConfig{Required: true, TrustMetadata: true}, "", "", codes.PermissionDenied, ""},
+	}
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/notes.NoteService/List"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.metadata != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-tenant-id", tt.metadata))
+			}
+			if tt.claim != "" {
+				ctx = utils.WithUserClaims(ctx, map[string]any{"tenant_id": tt.claim})
+			}
+
+			var gotTenant string
+			_, err := UnaryTenant(tt.cfg)(ctx, nil, info, func(ctx context.Context, _ any) (any, error) {
+				gotTenant = utils.TenantIDFromContext(ctx)
+				return nil, nil
+			})
+
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %s, want %s (%v)", code, tt.wantCode, err)
+			}
+			if gotTenant != tt.wantTenant {
+				t.Fatalf("tenant = %q, want %q", gotTenant, tt.wantTenant)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ginanjar-template-golang/shared-pkg/utils"
+	"go.uber.org/zap/zapcore"
+)
+
+// ==================================================
+// LOGGER WITH BOUND FIELDS
+// ==================================================
+
+// Logger writes through the package sinks and adds its bound fields to every entry.
+// Fields passed at the call site win over bound fields with the same key.
+type Logger struct {
+	fields    map[string]any
+	component string
+}
+
+var defaultLogger = &Logger{}
+
+type loggerContextKey struct{}
+
+// Default returns the logger behind the package-level functions.
+func Default() *Logger {
+	return defaultLogger
+}
+
+// With returns a child logger with extra key/value pairs bound, e.g.
+// l.With("request_id", id, "method", "GET").
+func (l *Logger) With(keyValues ...any) *Logger {
+	fields := make(map[string]any, len(keyValues)/2)
+	for i := 0; i < len(keyValues); i += 2 {
+		key, ok := keyValues[i].(string)
+		if !ok {
+			key = fmt.Sprintf("%v", keyValues[i])
+		}
+		if i+1 < len(keyValues) {
+			fields[key] = keyValues[i+1]
+		} else {
+			fields[key] = nil
+		}
+	}
+	return l.WithFields(fields)
+}
+
+// WithFields returns a child logger with fields bound.
+func (l *Logger) WithFields(fields map[string]any) *Logger {
+	merged := make(map[string]any, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &Logger{fields: merged, component: l.component}
+}
+
+// Component returns a child logger whose level can be overridden with
+// SetComponentLevel, e.g. logger.Default().Component("db").
+func (l *Logger) Component(name string) *Logger {
+	child := l.With("component", name)
+	child.component = name
+	return child
+}
+
+// WithContext returns a copy of ctx carrying l.
+func WithContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, l)
+}
+
+// FromContext returns the logger stored in ctx. Without one it returns the
+// default logger bound to the request_id carried in ctx, if any.
+func FromContext(ctx context.Context) *Logger {
+	if ctx == nil {
+		return defaultLogger
+	}
+	if l, ok := ctx.Value(loggerContextKey{}).(*Logger); ok && l != nil {
+		return l
+	}
+	if rc := utils.GetRequestContext(ctx); rc != nil && rc.RequestID != "" {
+		return defaultLogger.With("request_id", rc.RequestID)
+	}
+	return defaultLogger
+}
+
+// Component returns a child of the default logger, see Logger.Component.
+func Component(name string) *Logger {
+	return defaultLogger.Component(name)
+}
+
+func (l *Logger) write(level zapcore.Level, msg string, fields map[string]any, err []error) {
+	ensureInit()
+	if !enabled(level, l.component) {
+		return
+	}
+	log(level, msg, l.merge(fields, err))
+}
+
+func (l *Logger) merge(fields map[string]any, err []error) map[string]any {
+	merged := make(map[string]any, len(l.fields)+len(fields)+1)
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	if len(err) > 0 && err[0] != nil {
+		merged["error"] = err[0].Error()
+	}
+	return merged
+}
+
+func (l *Logger) Info(msg string, fields map[string]any) {
+	l.write(zapcore.InfoLevel, msg, fields, nil)
+}
+
+func (l *Logger) Warn(msg string, fields map[string]any, err ...error) {
+	l.write(zapcore.WarnLevel, msg, fields, err)
+}
+
+func (l *Logger) Error(msg string, fields map[string]any, err ...error) {
+	l.write(zapcore.ErrorLevel, msg, fields, err)
+}
+
+func (l *Logger) Debug(msg string, fields map[string]any, err ...error) {
+	l.write(zapcore.DebugLevel, msg, fields, err)
+}
+
+func (l *Logger) Trace(msg string, fields map[string]any, err ...error) {
+	l.write(TraceLevel, msg, fields, err)
+}
+
+func (l *Logger) LogMapLevel(level string, internalCode int, msg string, data any, err ...error) {
+	fields := map[string]any{
+		"internal_code": internalCode,
+		"data":          data,
+	}
+	if len(err) > 0 && err[0] != nil {
+		fields["error"] = err[0].Error()
+	}
+
+	switch level {
+	case "debug":
+		l.Debug(msg, fields)
+	case "info":
+		l.Info(msg, fields)
+	case "warn":
+		l.Warn(msg, fields)
+	default:
+		l.Error(msg, fields)
+	}
+}
@@ -0,0 +1,221 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// ==================================================
+// OUTPUT ENCODING
+// ==================================================
+
+type Encoding string
+
+const (
+	EncodingConsole Encoding = "console" // boxed, colored multi-line block (default)
+	EncodingJSON    Encoding = "json"    // one JSON object per line
+	EncodingLogfmt  Encoding = "logfmt"  // one key=value line
+)
+
+// packages skipped when resolving the caller of a log line: every package of
+// this module (logger, errors, response, middleware, repository, ...), so the
+// caller is the service code that triggered the entry
+var callerSkipPackages = []string{
+	"github.com/ginanjar-template-golang/shared-pkg.",
+	"github.com/ginanjar-template-golang/shared-pkg/",
+}
+
+func encode(enc Encoding, entry zapcore.Entry, fields map[string]any) string {
+	switch enc {
+	case EncodingJSON:
+		return encodeJSON(entry, fields)
+	case EncodingLogfmt:
+		return encodeLogfmt(entry, fields)
+	default:
+		return format(entry, fields)
+	}
+}
+
+// LevelName returns the lowercase name of level, including "trace".
+func LevelName(level zapcore.Level) string {
+	if level == TraceLevel {
+		return "trace"
+	}
+	return level.String()
+}
+
+// callerOf returns the first stack frame outside this module.
+func callerOf() zapcore.EntryCaller {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !isSkippedCaller(frame.Function) {
+			return zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, true)
+		}
+		if !more {
+			return zapcore.EntryCaller{}
+		}
+	}
+}
+
+func isSkippedCaller(function string) bool {
+	for _, pkg := range callerSkipPackages {
+		if strings.HasPrefix(function, pkg) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeFields returns a copy of fields with error values converted to strings
+// so they survive JSON encoding.
+func normalizeFields(fields map[string]any) map[string]any {
+	out := make(map[string]any, len(fields))
+	for k, v := range fields {
+		if e, ok := v.(error); ok {
+			v = e.Error()
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// splitFields pulls the well-known keys out of fields and returns the rest.
+func splitFields(fields map[string]any) (requestID, internalCode, errVal any, rest map[string]any) {
+	rest = make(map[string]any, len(fields))
+	for k, v := range normalizeFields(fields) {
+		switch k {
+		case "request_id":
+			requestID = v
+		case "internal_code":
+			internalCode = v
+		case "error":
+			errVal = v
+		default:
+			rest[k] = v
+		}
+	}
+	return requestID, internalCode, errVal, rest
+}
+
+// ==================================================
+// JSON ENCODER
+// ==================================================
+
+type jsonRecord struct {
+	Level        string         `json:"level"`
+	Time         string         `json:"time"`
+	Message      string         `json:"message"`
+	RequestID    any            `json:"request_id,omitempty"`
+	InternalCode any            `json:"internal_code,omitempty"`
+	Caller       string         `json:"caller,omitempty"`
+	Error        any            `json:"error,omitempty"`
+	Fields       map[string]any `json:"fields,omitempty"`
+}
+
+func encodeJSON(entry zapcore.Entry, fields map[string]any) string {
+	requestID, internalCode, errVal, rest := splitFields(fields)
+	rec := jsonRecord{
+		Level:        LevelName(entry.Level),
+		Time:         entry.Time.Format(time.RFC3339Nano),
+		Message:      entry.Message,
+		RequestID:    requestID,
+		InternalCode: internalCode,
+		Error:        errVal,
+	}
+	if entry.Caller.Defined {
+		rec.Caller = entry.Caller.TrimmedPath()
+	}
+	if len(rest) > 0 {
+		rec.Fields = rest
+	}
+
+	b, err := json.Marshal(rec)
+	if err != nil {
+		// unserializable field values, keep the line parseable
+		rec.Fields = map[string]any{"encode_error": err.Error(), "raw": fmt.Sprintf("%v", rest)}
+		b, _ = json.Marshal(rec)
+	}
+	return string(b) + "\n"
+}
+
+// ==================================================
+// LOGFMT ENCODER
+// ==================================================
+
+func encodeLogfmt(entry zapcore.Entry, fields map[string]any) string {
+	requestID, internalCode, errVal, rest := splitFields(fields)
+
+	var sb strings.Builder
+	writeLogfmtPair(&sb, "time", entry.Time.Format(time.RFC3339Nano))
+	writeLogfmtPair(&sb, "level", LevelName(entry.Level))
+	writeLogfmtPair(&sb, "msg", entry.Message)
+	if requestID != nil {
+		writeLogfmtPair(&sb, "request_id", requestID)
+	}
+	if internalCode != nil {
+		writeLogfmtPair(&sb, "internal_code", internalCode)
+	}
+	if entry.Caller.Defined {
+		writeLogfmtPair(&sb, "caller", entry.Caller.TrimmedPath())
+	}
+	if errVal != nil {
+		writeLogfmtPair(&sb, "error", errVal)
+	}
+
+	keys := make([]string, 0, len(rest))
+	for k := range rest {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		writeLogfmtPair(&sb, k, rest[k])
+	}
+
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+func writeLogfmtPair(sb *strings.Builder, key string, value any) {
+	if sb.Len() > 0 {
+		sb.WriteByte(' ')
+	}
+	sb.WriteString(key)
+	sb.WriteByte('=')
+	sb.WriteString(logfmtValue(value))
+}
+
+func logfmtValue(value any) string {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		s = v
+	case fmt.Stringer:
+		s = v.String()
+	case int, int32, int64, uint, uint32, uint64, float32, float64, bool:
+		return fmt.Sprintf("%v", v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			s = fmt.Sprintf("%v", v)
+		} else {
+			s = string(b)
+		}
+	}
+
+	if s == "" || strings.ContainsAny(s, " =\"\t\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,24 @@
+package logger
+
+import "testing"
+
+func TestIsSkippedCaller(t *testing.T) {
+	tests := []struct {
+		function string
+		skipped  bool
+	}{
+		{"github.com/ginanjar-template-golang/shared-pkg/logger.LogMapLevelCtx", true},
+		{"github.com/ginanjar-template-golang/shared-pkg/errors.NewCtx", true},
+		{"github.com/ginanjar-template-golang/shared-pkg/response/http_response.FromAppError", true},
+		{"github.com/ginanjar-template-golang/shared-pkg/db/gorm/repository.(*Repository[...]).FindByIDCtx", true},
+		{"github.com/ginanjar-template-golang/shared-pkg/middleware.Recovery.func1", true},
+		{"github.com/acme/user-service/internal/user.(*Service).Get", false},
+		{"github.com/ginanjar-template-golang/shared-pkg-client/api.Call", false},
+		{"main.main", false},
+	}
+	for _, tt := range tests {
+		if got := isSkippedCaller(tt.function); got != tt.skipped {
+			t.Errorf("isSkippedCaller(%q) = %v, want %v", tt.function, got, tt.skipped)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ==================================================
+// HTTP BULK SINK
+// ==================================================
+
+type OverflowPolicy int
+
+const (
+	OverflowDrop  OverflowPolicy = iota // drop new entries when the queue is full (default)
+	OverflowBlock                       // block the caller until the queue has room
+)
+
+var ErrSinkClosed = errors.New("logger: sink closed")
+
+type HTTPSinkConfig struct {
+	URL           string
+	Headers       map[string]string
+	BatchSize     int           // entries per request, default 100
+	FlushInterval time.Duration // max time an entry waits in the batch, default 2s
+	QueueSize     int           // buffered entries, default 1000
+	Overflow      OverflowPolicy
+	MaxRetries    int           // retries after the first attempt, default 3, negative disables
+	RetryBackoff  time.Duration // initial backoff, doubled every retry, default 200ms
+	MaxBackoff    time.Duration // cap of the doubled backoff, default 5s
+	Client        *http.Client  // default client with 5s timeout
+
+	// Encode renders one entry as a single line of the bulk body, default JSON.
+	Encode func(entry Entry) ([]byte, error)
+}
+
+// HTTPSink sends entries in newline-delimited batches to a bulk endpoint
+// from a single background worker.
+type HTTPSink struct {
+	cfg      HTTPSinkConfig
+	queue    chan Entry
+	flushReq chan chan error
+	done     chan struct{} // closed first, wakes blocked writers and stops retries
+	stop     chan struct{} // closed once no Write can enqueue anymore, stops the worker
+	wg       sync.WaitGroup
+
+	// writeMu is held shared by Write while it enqueues and exclusively by
+	// Close, so no entry lands in the queue after the final drain.
+	writeMu   sync.RWMutex
+	closeOnce sync.Once
+	closed    atomic.Bool
+	dropped   atomic.Uint64
+}
+
+func NewHTTPSink(cfg HTTPSinkConfig) *HTTPSink {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 2 * time.Second
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1000
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	} else if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = 200 * time.Millisecond
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 5 * time.Second
+	}
+	if cfg.MaxBackoff < cfg.RetryBackoff {
+		cfg.MaxBackoff = cfg.RetryBackoff
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 5 * time.Second}
+	}
+	if cfg.Encode == nil {
+		cfg.Encode = func(entry Entry) ([]byte, error) {
+			return []byte(encodeJSON(entry.Entry, entry.Fields)), nil
+		}
+	}
+
+	s := &HTTPSink{
+		cfg:      cfg,
+		queue:    make(chan Entry, cfg.QueueSize),
+		flushReq: make(chan chan error),
+		done:     make(chan struct{}),
+		stop:     make(chan struct{}),
+	}
+	s.wg.Add(1)
+	go s.run()
+	return s
+}
+
+func (s *HTTPSink) Write(entry Entry) error {
+	s.writeMu.RLock()
+	defer s.writeMu.RUnlock()
+	if s.closed.Load() {
+		return ErrSinkClosed
+	}
+
+	if s.cfg.Overflow == OverflowBlock {
+		select {
+		case s.queue <- entry:
+			return nil
+		case <-s.done:
+			return ErrSinkClosed
+		}
+	}
+
+	select {
+	case s.queue <- entry:
+	default:
+		s.dropped.Add(1)
+	}
+	return nil
+}
+
+// Dropped returns the number of entries discarded because the queue was full,
+// Encode failed for them or the endpoint kept failing after all retries.
+func (s *HTTPSink) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Flush sends every queued entry and waits for the result.
+func (s *HTTPSink) Flush() error {
+	if s.closed.Load() {
+		return ErrSinkClosed
+	}
+	reply := make(chan error, 1)
+	select {
+	case s.flushReq <- reply:
+		return <-reply
+	case <-s.done:
+		return ErrSinkClosed
+	}
+}
+
+// Close flushes the remaining entries and stops the worker.
+func (s *HTTPSink) Close() error {
+	s.closeOnce.Do(func() {
+		s.closed.Store(true)
+		close(s.done)
+
+		// wait for in-flight writes, later ones see closed
+		s.writeMu.Lock()
+		close(s.stop)
+		s.writeMu.Unlock()
+	})
+	s.wg.Wait()
+	return nil
+}
+
+func (s *HTTPSink) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Entry, 0, s.cfg.BatchSize)
+	send := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := s.send(batch)
+		batch = batch[:0]
+		return err
+	}
+	drain := func() error {
+		var firstErr error
+		for {
+			select {
+			case entry := <-s.queue:
+				batch = append(batch, entry)
+				if len(batch) >= s.cfg.BatchSize {
+					if err := send(); err != nil && firstErr == nil {
+						firstErr = err
+					}
+				}
+			default:
+				if err := send(); err != nil && firstErr == nil {
+					firstErr = err
+				}
+				return firstErr
+			}
+		}
+	}
+
+	for {
+		select {
+		case entry := <-s.queue:
+			batch = append(batch, entry)
+			if len(batch) >= s.cfg.BatchSize {
+				_ = send()
+			}
+		case <-ticker.C:
+			_ = send()
+		case reply := <-s.flushReq:
+			reply <- drain()
+		case <-s.stop:
+			_ = drain()
+			return
+		}
+	}
+}
+
+// send posts one batch, retrying with jittered exponential backoff on network
+// errors, 429 and 5xx responses. Once the sink is closing retries stop, so a
+// dead endpoint costs Close at most one attempt per remaining batch.
+func (s *HTTPSink) send(batch []Entry) error {
+	var body bytes.Buffer
+	sent := 0
+	for _, entry := range batch {
+		line, err := s.cfg.Encode(entry)
+		if err != nil {
+			s.dropped.Add(1)
+			continue
+		}
+		body.Write(bytes.TrimRight(line, "\n"))
+		body.WriteByte('\n')
+		sent++
+	}
+	if sent == 0 {
+		return nil
+	}
+
+	var lastErr error
+	backoff := s.cfg.RetryBackoff
+	for attempt := 0; attempt <= s.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if !s.wait(backoff) {
+				break
+			}
+			backoff = min(backoff*2, s.cfg.MaxBackoff)
+		}
+
+		retry, err := s.post(body.Bytes())
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+
+	s.dropped.Add(uint64(sent))
+	fmt.Fprintf(os.Stderr, "[logger] http sink dropped %d entries: %v\n", sent, lastErr)
+	return lastErr
+}
+
+// wait sleeps between half and all of backoff, false when the sink closes first.
+func (s *HTTPSink) wait(backoff time.Duration) bool {
+	half := backoff / 2
+	timer := time.NewTimer(half + rand.N(backoff-half+1))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+func (s *HTTPSink) post(body []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, s.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "text/plain")
+	for k, v := range s.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.cfg.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("http sink: unexpected status %d", resp.StatusCode)
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// ==================================================
+// LOGGLY SINK
+// ==================================================
+
+// DefaultLogglyBulkUrl is the Loggly bulk endpoint, formatted with token and tag.
+const DefaultLogglyBulkUrl = "https://logs-01.loggly.com/bulk/%s/tag/%s/"
+
+// NewLogglySink sends entries to the Loggly bulk endpoint built from c.LogglyUrl
+// (or DefaultLogglyBulkUrl), c.LogglyToken and c.LogglyTag. A single event
+// LogglyUrl (/inputs/) is rewritten to its /bulk/ counterpart, since the sink
+// posts newline-delimited batches that /inputs/ would store as one event.
+// URL and Encode in sinkCfg are overwritten, the remaining fields tune batching.
+func NewLogglySink(c Config, sinkCfg HTTPSinkConfig) *HTTPSink {
+	sinkCfg.URL = fmt.Sprintf(logglyBulkUrl(c.LogglyUrl), c.LogglyToken, c.LogglyTag)
+
+	hostname := getHostname()
+	sinkCfg.Encode = func(entry Entry) ([]byte, error) {
+		return json.Marshal(map[string]any{
+			"timestamp": entry.Time.Format(time.RFC3339),
+			"level":     LevelName(entry.Level),
+			"message":   entry.Message,
+			"fields":    normalizeFields(entry.Fields),
+			"hostname":  hostname,
+			"env":       c.Environment,
+		})
+	}
+
+	return NewHTTPSink(sinkCfg)
+}
+
+// logglyBulkUrl returns url with the Loggly /inputs/ path replaced by /bulk/.
+func logglyBulkUrl(url string) string {
+	if url == "" {
+		return DefaultLogglyBulkUrl
+	}
+	return strings.Replace(url, "/inputs/", "/bulk/", 1)
+}
+
+func getHostname() string {
+	host, err := os.Hostname()
+	if err != nil {
+		return "unknown-host"
+	}
+	return host
+}
@@ -0,0 +1,436 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// bulkServer records every request body as its lines; status picks the
+// response of the n-th request (0-based), 200 when nil.
+type bulkServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	batches  [][]string
+	times    []time.Time
+	headers  []http.Header
+	status   func(n int) int
+	received chan struct{}
+	release  chan struct{} // when set, handlers wait for it before answering
+}
+
+func newBulkServer(t *testing.T) *bulkServer {
+	t.Helper()
+	s := &bulkServer{received: make(chan struct{}, 100)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.mu.Lock()
+		n := len(s.batches)
+		s.batches = append(s.batches, strings.Split(strings.TrimSuffix(string(body), "\n"), "\n"))
+		s.times = append(s.times, time.Now())
+		s.headers = append(s.headers, r.Header.Clone())
+		status, release := http.StatusOK, s.release
+		if s.status != nil {
+			status = s.status(n)
+		}
+		s.mu.Unlock()
+
+		s.received <- struct{}{}
+		if release != nil {
+			<-release
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *bulkServer) snapshot() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]string(nil), s.batches...)
+}
+
+func (s *bulkServer) waitRequest(t *testing.T) {
+	t.Helper()
+	select {
+	case <-s.received:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no request reached the server")
+	}
+}
+
+func testEntry(msg string) Entry {
+	return Entry{Entry: zapcore.Entry{Level: zapcore.InfoLevel, Time: time.Now(), Message: msg}}
+}
+
+func countLines(batches [][]string) int {
+	n := 0
+	for _, b := range batches {
+		n += len(b)
+	}
+	return n
+}
+
+func TestHTTPSinkBatching(t *testing.T) {
+	srv := newBulkServer(t)
+	sink := NewHTTPSink(HTTPSinkConfig{
+		URL:           srv.URL,
+		Headers:       map[string]string{"X-Api-Key": "secret"},
+		BatchSize:     3,
+		FlushInterval: time.Hour,
+	})
+	defer sink.Close()
+
+	for i := 0; i < 7; i++ {
+		if err := sink.Write(testEntry("entry")); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	batches := srv.snapshot()
+	sizes := make([]int, len(batches))
+	for i, b := range batches {
+		sizes[i] = len(b)
+	}
+	if len(sizes) != 3 || sizes[0] != 3 || sizes[1] != 3 || sizes[2] != 1 {
+		t.Fatalf("batch sizes = %v, want [3 3 1]", sizes)
+	}
+	if !strings.Contains(batches[0][0], `"message":"entry"`) {
+		t.Fatalf("line = %q, want the JSON encoded entry", batches[0][0])
+	}
+	if got := srv.headers[0].Get("X-Api-Key"); got != "secret" {
+		t.Fatalf("X-Api-Key = %q, want secret", got)
+	}
+}
+
+func TestHTTPSinkFlushInterval(t *testing.T) {
+	srv := newBulkServer(t)
+	sink := NewHTTPSink(HTTPSinkConfig{URL: srv.URL, BatchSize: 100, FlushInterval: 20 * time.Millisecond})
+	defer sink.Close()
+
+	_ = sink.Write(testEntry("a"))
+	_ = sink.Write(testEntry("b"))
+	srv.waitRequest(t)
+
+	if n := countLines(srv.snapshot()); n != 2 {
+		t.Fatalf("sent %d entries, want 2", n)
+	}
+}
+
+func TestHTTPSinkEncodeFailures(t *testing.T) {
+	srv := newBulkServer(t)
+	sink := NewHTTPSink(HTTPSinkConfig{
+		URL:           srv.URL,
+		BatchSize:     10,
+		FlushInterval: time.Hour,
+		Encode: func(entry Entry) ([]byte, error) {
+			if entry.Message == "bad" {
+				return nil, errors.New("unencodable")
+			}
+			return []byte(entry.Message), nil
+		},
+	})
+	defer sink.Close()
+
+	_ = sink.Write(testEntry("bad"))
+	_ = sink.Write(testEntry("good"))
+	_ = sink.Write(testEntry("bad"))
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if got := sink.Dropped(); got != 2 {
+		t.Fatalf("dropped = %d, want 2", got)
+	}
+	if batches := srv.snapshot(); len(batches) != 1 || len(batches[0]) != 1 || batches[0][0] != "good" {
+		t.Fatalf("batches = %v, want [[good]]", batches)
+	}
+
+	// a batch with nothing encodable is not posted at all
+	_ = sink.Write(testEntry("bad"))
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if got := len(srv.snapshot()); got != 1 {
+		t.Fatalf("requests = %d, want 1 (no empty POST)", got)
+	}
+	if got := sink.Dropped(); got != 3 {
+		t.Fatalf("dropped = %d, want 3", got)
+	}
+}
+
+func TestHTTPSinkOverflowDrop(t *testing.T) {
+	srv := newBulkServer(t)
+	srv.release = make(chan struct{})
+	sink := NewHTTPSink(HTTPSinkConfig{URL: srv.URL, BatchSize: 1, QueueSize: 1, FlushInterval: time.Hour})
+
+	_ = sink.Write(testEntry("in flight"))
+	srv.waitRequest(t) // the worker is stuck sending the first entry
+
+	_ = sink.Write(testEntry("queued"))
+	for i := 0; i < 3; i++ {
+		if err := sink.Write(testEntry("dropped")); err != nil {
+			t.Fatalf("write on full queue = %v, want nil (dropped)", err)
+		}
+	}
+	if got := sink.Dropped(); got != 3 {
+		t.Fatalf("Dropped() = %d, want 3", got)
+	}
+
+	close(srv.release)
+	if err := sink.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if n := countLines(srv.snapshot()); n != 2 {
+		t.Fatalf("sent %d entries, want 2", n)
+	}
+}
+
+func TestHTTPSinkOverflowBlock(t *testing.T) {
+	srv := newBulkServer(t)
+	srv.release = make(chan struct{})
+	sink := NewHTTPSink(HTTPSinkConfig{URL: srv.URL, BatchSize: 1, QueueSize: 1, FlushInterval: time.Hour, Overflow: OverflowBlock})
+
+	_ = sink.Write(testEntry("in flight"))
+	srv.waitRequest(t)
+	_ = sink.Write(testEntry("queued"))
+
+	written := make(chan error, 1)
+	go func() { written <- sink.Write(testEntry("blocked")) }()
+
+	select {
+	case err := <-written:
+		t.Fatalf("write returned %v on a full queue, want it to block", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(srv.release)
+	select {
+	case err := <-written:
+		if err != nil {
+			t.Fatalf("blocked write: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("write still blocked after the queue drained")
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if n := countLines(srv.snapshot()); n != 3 {
+		t.Fatalf("sent %d entries, want 3", n)
+	}
+	if got := sink.Dropped(); got != 0 {
+		t.Fatalf("Dropped() = %d, want 0", got)
+	}
+}
+
+func TestHTTPSinkRetryBackoff(t *testing.T) {
+	srv := newBulkServer(t)
+	srv.status = func(n int) int {
+		if n < 2 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	}
+	backoff := 20 * time.Millisecond
+	sink := NewHTTPSink(HTTPSinkConfig{URL: srv.URL, FlushInterval: time.Hour, MaxRetries: 3, RetryBackoff: backoff})
+	defer sink.Close()
+
+	_ = sink.Write(testEntry("a"))
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	srv.mu.Lock()
+	times := append([]time.Time(nil), srv.times...)
+	srv.mu.Unlock()
+	if len(times) != 3 {
+		t.Fatalf("attempts = %d, want 3", len(times))
+	}
+	// jitter waits between half and all of the backoff
+	if gap := times[1].Sub(times[0]); gap < backoff/2 {
+		t.Fatalf("first retry after %v, want >= %v", gap, backoff/2)
+	}
+	if gap := times[2].Sub(times[1]); gap < backoff {
+		t.Fatalf("second retry after %v, want >= %v (doubled)", gap, backoff)
+	}
+	if got := sink.Dropped(); got != 0 {
+		t.Fatalf("Dropped() = %d, want 0", got)
+	}
+}
+
+func TestHTTPSinkMaxBackoff(t *testing.T) {
+	srv := newBulkServer(t)
+	srv.status = func(n int) int {
+		if n < 4 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	}
+	maxBackoff := 30 * time.Millisecond
+	sink := NewHTTPSink(HTTPSinkConfig{
+		URL:           srv.URL,
+		FlushInterval: time.Hour,
+		MaxRetries:    4,
+		RetryBackoff:  20 * time.Millisecond,
+		MaxBackoff:    maxBackoff,
+	})
+	defer sink.Close()
+
+	_ = sink.Write(testEntry("a"))
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	srv.mu.Lock()
+	times := append([]time.Time(nil), srv.times...)
+	srv.mu.Unlock()
+	if len(times) != 5 {
+		t.Fatalf("attempts = %d, want 5", len(times))
+	}
+	// without the cap the last wait would be at least 80ms
+	if gap := times[4].Sub(times[3]); gap > maxBackoff+50*time.Millisecond {
+		t.Fatalf("last retry after %v, want about %v at most", gap, maxBackoff)
+	}
+}
+
+func TestHTTPSinkCloseStopsRetrying(t *testing.T) {
+	srv := newBulkServer(t)
+	srv.status = func(int) int { return http.StatusServiceUnavailable }
+	sink := NewHTTPSink(HTTPSinkConfig{URL: srv.URL, BatchSize: 1, MaxRetries: 5, RetryBackoff: time.Hour})
+
+	_ = sink.Write(testEntry("a"))
+	srv.waitRequest(t)
+
+	start := time.Now()
+	if err := sink.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("close took %v, want it not to wait for the backoff", elapsed)
+	}
+	if got := sink.Dropped(); got != 1 {
+		t.Fatalf("Dropped() = %d, want 1", got)
+	}
+}
+
+func TestHTTPSinkRetryGivesUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		attempts int
+	}{
+		{"server error exhausts retries", http.StatusInternalServerError, 3},
+		{"rate limit exhausts retries", http.StatusTooManyRequests, 3},
+		{"client error is not retried", http.StatusBadRequest, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newBulkServer(t)
+			srv.status = func(int) int { return tt.status }
+			sink := NewHTTPSink(HTTPSinkConfig{URL: srv.URL, FlushInterval: time.Hour, MaxRetries: 2, RetryBackoff: time.Millisecond})
+			defer sink.Close()
+
+			_ = sink.Write(testEntry("a"))
+			_ = sink.Write(testEntry("b"))
+			if err := sink.Flush(); err == nil {
+				t.Fatal("flush = nil, want the last send error")
+			}
+			if n := len(srv.snapshot()); n != tt.attempts {
+				t.Fatalf("attempts = %d, want %d", n, tt.attempts)
+			}
+			if got := sink.Dropped(); got != 2 {
+				t.Fatalf("Dropped() = %d, want 2", got)
+			}
+		})
+	}
+}
+
+func TestHTTPSinkClose(t *testing.T) {
+	srv := newBulkServer(t)
+	sink := NewHTTPSink(HTTPSinkConfig{URL: srv.URL, BatchSize: 100, FlushInterval: time.Hour})
+
+	_ = sink.Write(testEntry("a"))
+	_ = sink.Write(testEntry("b"))
+	if err := sink.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if n := countLines(srv.snapshot()); n != 2 {
+		t.Fatalf("close flushed %d entries, want 2", n)
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatalf("second close: %v", err)
+	}
+	if err := sink.Write(testEntry("late")); !errors.Is(err, ErrSinkClosed) {
+		t.Fatalf("write after close = %v, want ErrSinkClosed", err)
+	}
+	if err := sink.Flush(); !errors.Is(err, ErrSinkClosed) {
+		t.Fatalf("flush after close = %v, want ErrSinkClosed", err)
+	}
+}
+
+func TestHTTPSinkCloseWhileWriting(t *testing.T) {
+	for _, overflow := range []OverflowPolicy{OverflowDrop, OverflowBlock} {
+		srv := newBulkServer(t)
+		sink := NewHTTPSink(HTTPSinkConfig{URL: srv.URL, BatchSize: 10, QueueSize: 8, FlushInterval: time.Hour, Overflow: overflow})
+
+		const writers, perWriter = 8, 50
+		var rejected atomic.Uint64
+		var wg sync.WaitGroup
+		for w := 0; w < writers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < perWriter; i++ {
+					if err := sink.Write(testEntry("x")); err != nil {
+						rejected.Add(1)
+					}
+				}
+			}()
+		}
+		time.Sleep(time.Millisecond)
+		if err := sink.Close(); err != nil {
+			t.Fatalf("close: %v", err)
+		}
+		wg.Wait()
+
+		// every entry is either sent, counted as dropped or rejected, none vanish
+		sent := uint64(countLines(srv.snapshot()))
+		if got := sent + sink.Dropped() + rejected.Load(); got != writers*perWriter {
+			t.Fatalf("overflow %d: sent %d + dropped %d + rejected %d = %d, want %d",
+				overflow, sent, sink.Dropped(), rejected.Load(), got, writers*perWriter)
+		}
+	}
+}
+
+func TestLogglyBulkUrl(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"", DefaultLogglyBulkUrl},
+		{"https://logs-01.loggly.com/inputs/%s/tag/%s/", "https://logs-01.loggly.com/bulk/%s/tag/%s/"},
+		{"https://logs-01.loggly.com/bulk/%s/tag/%s/", "https://logs-01.loggly.com/bulk/%s/tag/%s/"},
+		{"https://proxy.internal/loggly/%s/%s", "https://proxy.internal/loggly/%s/%s"},
+	}
+	for _, tt := range tests {
+		if got := logglyBulkUrl(tt.url); got != tt.want {
+			t.Errorf("logglyBulkUrl(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// ==================================================
+// RUNTIME LEVEL CONTROL
+// ==================================================
+
+var (
+	globalLevel    atomic.Int32
+	globalLevelSet atomic.Bool // SetLevel was called, Init keeps that level
+
+	componentLevels   = map[string]zapcore.Level{}
+	componentLevelsMu sync.RWMutex
+)
+
+// ParseLevel parses trace, debug, info, warn and error (case insensitive).
+func ParseLevel(s string) (zapcore.Level, error) {
+	if strings.EqualFold(strings.TrimSpace(s), "trace") {
+		return TraceLevel, nil
+	}
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(strings.ToLower(strings.TrimSpace(s)))); err != nil {
+		return level, fmt.Errorf("logger: unknown level %q", s)
+	}
+	return level, nil
+}
+
+// SetLevel changes the global minimum level at runtime.
+func SetLevel(level zapcore.Level) {
+	globalLevel.Store(int32(level))
+	globalLevelSet.Store(true)
+}
+
+// GetLevel returns the global minimum level.
+func GetLevel() zapcore.Level {
+	return zapcore.Level(globalLevel.Load())
+}
+
+// SetComponentLevel overrides the minimum level for one component, e.g. "db".
+func SetComponentLevel(component string, level zapcore.Level) {
+	componentLevelsMu.Lock()
+	defer componentLevelsMu.Unlock()
+	componentLevels[component] = level
+}
+
+// ResetComponentLevel removes a component override so the global level applies again.
+func ResetComponentLevel(component string) {
+	componentLevelsMu.Lock()
+	defer componentLevelsMu.Unlock()
+	delete(componentLevels, component)
+}
+
+// ComponentLevels returns a snapshot of the component overrides.
+func ComponentLevels() map[string]zapcore.Level {
+	componentLevelsMu.RLock()
+	defer componentLevelsMu.RUnlock()
+	out := make(map[string]zapcore.Level, len(componentLevels))
+	for k, v := range componentLevels {
+		out[k] = v
+	}
+	return out
+}
+
+// SetLevelSpec applies a comma separated spec such as "info,db=debug,http=warn".
+// A bare level sets the global level, component=level sets an override.
+// Nothing is applied when any part of the spec is invalid.
+func SetLevelSpec(spec string) error {
+	global, components, err := parseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+	if global != nil {
+		SetLevel(*global)
+	}
+	for component, level := range components {
+		SetComponentLevel(component, level)
+	}
+	return nil
+}
+
+// applyDefaultLevels sets the levels derived from c (environment, AllLogLevel,
+// LevelSpec) that were not set explicitly before Init.
+func applyDefaultLevels(c Config) {
+	level := detectLevel(c.Environment)
+	if c.AllLogLevel {
+		level = TraceLevel
+	}
+	var components map[string]zapcore.Level
+	if c.LevelSpec != "" {
+		global, parsed, err := parseLevelSpec(c.LevelSpec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[logger] invalid LevelSpec: %v\n", err)
+		} else {
+			if global != nil {
+				level = *global
+			}
+			components = parsed
+		}
+	}
+
+	if !globalLevelSet.Load() {
+		globalLevel.Store(int32(level))
+	}
+	componentLevelsMu.Lock()
+	defer componentLevelsMu.Unlock()
+	for component, level := range components {
+		if _, set := componentLevels[component]; !set {
+			componentLevels[component] = level
+		}
+	}
+}
+
+// LevelSpec returns the current levels in the SetLevelSpec format.
+func LevelSpec() string {
+	parts := []string{LevelName(GetLevel())}
+	overrides := ComponentLevels()
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%s", name, LevelName(overrides[name])))
+	}
+	return strings.Join(parts, ",")
+}
+
+func parseLevelSpec(spec string) (*zapcore.Level, map[string]zapcore.Level, error) {
+	var global *zapcore.Level
+	components := map[string]zapcore.Level{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, hasName := strings.Cut(part, "=")
+		if !hasName {
+			value = name
+		}
+		level, err := ParseLevel(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		if hasName {
+			components[strings.TrimSpace(name)] = level
+		} else {
+			global = &level
+		}
+	}
+	return global, components, nil
+}
+
+// enabled reports whether level passes the component override, or the global level.
+func enabled(level zapcore.Level, component string) bool {
+	if component != "" {
+		componentLevelsMu.RLock()
+		min, ok := componentLevels[component]
+		componentLevelsMu.RUnlock()
+		if ok {
+			return level >= min
+		}
+	}
+	return level >= GetLevel()
+}
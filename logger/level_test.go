@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"sync"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// resetLevels returns the package to its pre-Init state for one test.
+func resetLevels(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		once = sync.Once{}
+		globalLevel.Store(int32(zapcore.InfoLevel))
+		globalLevelSet.Store(false)
+		componentLevelsMu.Lock()
+		componentLevels = map[string]zapcore.Level{}
+		componentLevelsMu.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestInitKeepsLevelsSetBefore(t *testing.T) {
+	resetLevels(t)
+
+	SetLevel(zapcore.ErrorLevel)
+	SetComponentLevel("db", zapcore.DebugLevel)
+	Init(Config{Environment: "dev", LevelSpec: "info,db=warn,http=warn", Sinks: []Sink{NewMemorySink()}})
+
+	if got := GetLevel(); got != zapcore.ErrorLevel {
+		t.Errorf("global level = %s, want the explicit error", LevelName(got))
+	}
+	levels := ComponentLevels()
+	if got := levels["db"]; got != zapcore.DebugLevel {
+		t.Errorf("db level = %s, want the explicit debug", LevelName(got))
+	}
+	if got, ok := levels["http"]; !ok || got != zapcore.WarnLevel {
+		t.Errorf("http level = %s (set %v), want warn from LevelSpec", LevelName(got), ok)
+	}
+}
+
+func TestInitAppliesEnvironmentLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want zapcore.Level
+	}{
+		{"prod", Config{Environment: "prod"}, zapcore.WarnLevel},
+		{"all levels", Config{Environment: "prod", AllLogLevel: true}, TraceLevel},
+		{"level spec", Config{Environment: "prod", LevelSpec: "debug"}, zapcore.DebugLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetLevels(t)
+			tt.cfg.Sinks = []Sink{NewMemorySink()}
+			Init(tt.cfg)
+			if got := GetLevel(); got != tt.want {
+				t.Errorf("level = %s, want %s", LevelName(got), LevelName(tt.want))
+			}
+		})
+	}
+}
@@ -1,10 +1,9 @@
 package logger
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"os"
 	"strings"
 	"sync"
@@ -19,30 +18,48 @@ import (
 // ==================================================
 
 type Config struct {
-	LogglyUrl   string // URL from Loggly https://logs-01.loggly.com/inputs/%s/tag/%s/
-	LogglyToken string // Token from Loggly
-	LogglyTag   string // Nama tag example: "user-service"
-	Environment string // dev, staging, prod
-	AllLogLevel bool   // optional for show all level log ignoring the nvirontment
+	LogglyUrl   string         // URL from Loggly https://logs-01.loggly.com/inputs/%s/tag/%s/, sent to /bulk/
+	LogglyToken string         // Token from Loggly
+	LogglyTag   string         // Nama tag example: "user-service"
+	LogglySink  HTTPSinkConfig // optional batching / retry tuning for the Loggly sink
+	Environment string         // dev, staging, prod
+	AllLogLevel bool           // optional for show all level log ignoring the nvirontment
+	Encoding    Encoding       // console (default) | json | logfmt
+	Sinks       []Sink         // optional, replaces the default stdout (+ Loggly) sinks
+	LevelSpec   string         // optional, overrides the environment level: "info,db=debug,http=warn"
 }
 
 var (
-	cfg         Config
-	logLevel    zapcore.Level
-	once        sync.Once
-	initialized bool
+	cfg  Config
+	once sync.Once
+
+	sinks   []Sink
+	sinksMu sync.RWMutex
 )
 
 // ==================================================
 // INITIALIZATION
 // ==================================================
 
+// Init configures the logger once. Levels set by SetLevel, SetComponentLevel or
+// SetLevelSpec before Init are kept, the environment and LevelSpec only fill in
+// what was not set explicitly.
 func Init(c Config) {
 	once.Do(func() {
 		cfg = c
-		logLevel = detectLevel(c.Environment)
-		initialized = true
-		fmt.Println("Logger initialized with env:", c.Environment)
+		applyDefaultLevels(c)
+
+		sinksMu.Lock()
+		if len(c.Sinks) > 0 {
+			sinks = append([]Sink(nil), c.Sinks...)
+		} else {
+			sinks = defaultSinks(c)
+		}
+		sinksMu.Unlock()
+
+		if c.Encoding == "" || c.Encoding == EncodingConsole {
+			fmt.Println("Logger initialized with env:", c.Environment)
+		}
 	})
 }
 
@@ -131,104 +148,99 @@ func format(entry zapcore.Entry, fields map[string]any) string {
 // CORE LOG FUNCTION
 // ==================================================
 
-func log(level zapcore.Level, msg string, fields map[string]any) {
-	if !initialized {
-		Init(Config{Environment: "dev", AllLogLevel: true})
-	}
-
-	// Filter by environment level
-	if !cfg.AllLogLevel && level < logLevel {
-		return
-	}
+// ensureInit initializes the logger with a dev config unless Init ran first.
+func ensureInit() {
+	Init(Config{Environment: "dev", AllLogLevel: true})
+}
 
+// log writes an entry to every sink. Level filtering happens in Logger.write.
+func log(level zapcore.Level, msg string, fields map[string]any) {
 	entry := zapcore.Entry{
 		Level:   level,
 		Time:    time.Now(),
 		Message: msg,
 	}
 
-	fmt.Fprint(os.Stdout, format(entry, fields))
+	entry.Caller = callerOf()
+
+	// copy so async sinks never see later mutations by the caller
+	e := Entry{Entry: entry, Fields: normalizeFields(fields)}
 
-	// Kirim ke Loggly hanya jika environment bukan dev
-	if cfg.LogglyToken != "" && cfg.Environment != "dev" {
-		go sendToLoggly(entry, fields)
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	for _, s := range sinks {
+		if err := s.Write(e); err != nil {
+			fmt.Fprintf(os.Stderr, "[logger] sink write failed: %v\n", err)
+		}
 	}
 }
 
 // ==================================================
-// PRIVATE: LOGGLY TRANSPORTER
+// SINK MANAGEMENT
 // ==================================================
 
-func sendToLoggly(entry zapcore.Entry, fields map[string]any) {
-	url := fmt.Sprintf(cfg.LogglyUrl,
-		cfg.LogglyToken,
-		cfg.LogglyTag,
-	)
-
-	payload := map[string]any{
-		"timestamp": entry.Time.Format(time.RFC3339),
-		"level":     entry.Level.String(),
-		"message":   entry.Message,
-		"fields":    fields,
-		"hostname":  getHostname(),
-		"env":       cfg.Environment,
+// defaultSinks builds the sinks used when Config.Sinks is empty:
+// stdout, plus Loggly when a token is set and the environment is not dev.
+func defaultSinks(c Config) []Sink {
+	out := []Sink{NewStdoutSink(c.Encoding)}
+	if c.LogglyToken != "" && c.Environment != "dev" {
+		out = append(out, NewLogglySink(c, c.LogglySink))
 	}
+	return out
+}
 
-	body, _ := json.Marshal(payload)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
-	if err != nil {
-		return
-	}
+// AddSink registers an additional sink at runtime.
+func AddSink(s Sink) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	sinks = append(sinks, s)
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{Timeout: 3 * time.Second}
-	client.Do(req)
+// Flush flushes every sink, returning the first error.
+func Flush() error {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	var firstErr error
+	for _, s := range sinks {
+		if err := s.Flush(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
 }
 
-func getHostname() string {
-	host, err := os.Hostname()
-	if err != nil {
-		return "unknown-host"
+// Close flushes and closes every sink. Call it on graceful shutdown.
+func Close() error {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	var firstErr error
+	for _, s := range sinks {
+		if err := s.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
-	return host
+	sinks = nil
+	return firstErr
 }
 
 // ==================================================
-// PUBLIC LOGGER FUNCTIONS
+// PUBLIC LOGGER FUNCTIONS (DEFAULT LOGGER)
 // ==================================================
 
 func Info(msg string, fields map[string]any) {
-	log(zapcore.InfoLevel, msg, fields)
+	defaultLogger.Info(msg, fields)
 }
 
 func Warn(msg string, fields map[string]any, err ...error) {
-	if fields == nil {
-		fields = make(map[string]any)
-	}
-	if len(err) > 0 && err[0] != nil {
-		fields["error"] = err[0].Error()
-	}
-	log(zapcore.WarnLevel, msg, fields)
+	defaultLogger.Warn(msg, fields, err...)
 }
 
 func Error(msg string, fields map[string]any, err ...error) {
-	if fields == nil {
-		fields = make(map[string]any)
-	}
-	if len(err) > 0 && err[0] != nil {
-		fields["error"] = err[0].Error()
-	}
-	log(zapcore.ErrorLevel, msg, fields)
+	defaultLogger.Error(msg, fields, err...)
 }
 
 func Debug(msg string, fields map[string]any, err ...error) {
-	if fields == nil {
-		fields = make(map[string]any)
-	}
-	if len(err) > 0 && err[0] != nil {
-		fields["error"] = err[0].Error()
-	}
-	log(zapcore.DebugLevel, msg, fields)
+	defaultLogger.Debug(msg, fields, err...)
 }
 
 // ==================================================
@@ -238,33 +250,52 @@ func Debug(msg string, fields map[string]any, err ...error) {
 const TraceLevel zapcore.Level = -2
 
 func Trace(msg string, fields map[string]any, err ...error) {
-	if fields == nil {
-		fields = make(map[string]any)
-	}
-	if len(err) > 0 && err[0] != nil {
-		fields["error"] = err[0].Error()
+	defaultLogger.Trace(msg, fields, err...)
+}
+
+// ==================================================
+// CONTEXT-AWARE LOGGER FUNCTIONS
+// ==================================================
+
+// loggerFor returns the logger carried in ctx, making sure request_id is bound
+// when ctx has one.
+func loggerFor(ctx context.Context) *Logger {
+	l := FromContext(ctx)
+	if _, ok := l.fields["request_id"]; !ok {
+		if reqID := utils.RequestIDFromContext(ctx); reqID != "" {
+			l = l.With("request_id", reqID)
+		}
 	}
-	log(TraceLevel, msg, fields)
+	return l
+}
+
+func InfoCtx(ctx context.Context, msg string, fields map[string]any) {
+	loggerFor(ctx).Info(msg, fields)
+}
+
+func WarnCtx(ctx context.Context, msg string, fields map[string]any, err ...error) {
+	loggerFor(ctx).Warn(msg, fields, err...)
+}
+
+func ErrorCtx(ctx context.Context, msg string, fields map[string]any, err ...error) {
+	loggerFor(ctx).Error(msg, fields, err...)
+}
+
+func DebugCtx(ctx context.Context, msg string, fields map[string]any, err ...error) {
+	loggerFor(ctx).Debug(msg, fields, err...)
+}
+
+func TraceCtx(ctx context.Context, msg string, fields map[string]any, err ...error) {
+	loggerFor(ctx).Trace(msg, fields, err...)
 }
 
 // ===============================================
 // LOG HELPER (LEVEL AWARE)
 // ===============================================
 func LogMapLevel(level string, internalCode int, msg string, data any, err ...error) {
-	fields := map[string]any{
-		"request_id":    utils.GetRequestID(),
-		"internal_code": internalCode,
-		"data":          data,
-	}
+	LogMapLevelCtx(context.Background(), level, internalCode, msg, data, err...)
+}
 
-	switch level {
-	case "debug":
-		Debug(msg, fields, err...)
-	case "info":
-		Info(msg, fields)
-	case "warn":
-		Warn(msg, fields, err...)
-	default:
-		Error(msg, fields, err...)
-	}
+func LogMapLevelCtx(ctx context.Context, level string, internalCode int, msg string, data any, err ...error) {
+	loggerFor(ctx).LogMapLevel(level, internalCode, msg, data, err...)
 }
@@ -11,7 +11,7 @@ import (
 
 func configLogger() {
 	logger.Init(logger.Config{
-		LogglyUrl:   "https://logs-01.loggly.com/inputs/%s/tag/%s",
+		LogglyUrl:   "https://logs-01.loggly.com/bulk/%s/tag/%s/",
 		LogglyToken: "", //your-loggly-token
 		LogglyTag:   "service-shared-pkg",
 		Environment: "dev", // dev (TRACE,DEBUG,INFO,WARN,ERROR) | staging (TRACE,INFO,WARN,ERROR) | prod (WARN,ERROR)
@@ -24,6 +24,7 @@ func main() {
 	r := gin.Default()
 
 	configLogger()
+	defer logger.Close()
 
 	r.Use(middleware.CORS())
 	r.Use(middleware.RequestLogger())
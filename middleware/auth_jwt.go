@@ -7,6 +7,7 @@ import (
 	"github.com/ginanjar-template-golang/shared-pkg/errors"
 	"github.com/ginanjar-template-golang/shared-pkg/logger"
 	httpResponse "github.com/ginanjar-template-golang/shared-pkg/response/http_response"
+	"github.com/ginanjar-template-golang/shared-pkg/utils"
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -16,14 +17,15 @@ type JWTConfig struct {
 
 func AuthJWT(cfg JWTConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		ctx := c.Request.Context()
 		authHeader := c.GetHeader("Authorization")
 
 		if authHeader == "" {
-			logger.Warn("Missing Authorization header", map[string]any{
+			logger.WarnCtx(ctx, "Missing Authorization header", map[string]any{
 				"path": c.Request.URL.Path,
 			})
 
-			httpResponse.FromAppError(c, errors.ValueMissMatch("Missing Authorization header", nil))
+			httpResponse.FromAppError(c, errors.ValueMissMatchCtx(ctx, "Missing Authorization header", nil))
 			c.Abort()
 			return
 		}
@@ -34,12 +36,12 @@ func AuthJWT(cfg JWTConfig) gin.HandlerFunc {
 		})
 
 		if err != nil || !token.Valid {
-			logger.Warn("Invalid JWT token", map[string]any{
+			logger.WarnCtx(ctx, "Invalid JWT token", map[string]any{
 				"error": err,
 				"path":  c.Request.URL.Path,
 			})
 
-			httpResponse.FromAppError(c, errors.InvalidTokenError("Token", err))
+			httpResponse.FromAppError(c, errors.InvalidTokenErrorCtx(ctx, "Token", err))
 			c.Abort()
 			return
 		}
@@ -47,6 +49,11 @@ func AuthJWT(cfg JWTConfig) gin.HandlerFunc {
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if ok {
 			c.Set("user_claims", claims)
+			ctx = utils.WithUserClaims(ctx, claims)
+			if sub, ok := claims["sub"]; ok {
+				ctx = logger.WithContext(ctx, logger.FromContext(ctx).With("user_id", sub))
+			}
+			c.Request = c.Request.WithContext(ctx)
 		}
 
 		c.Next()
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ginanjar-template-golang/shared-pkg/errors"
+	"github.com/ginanjar-template-golang/shared-pkg/logger"
+	httpResponse "github.com/ginanjar-template-golang/shared-pkg/response/http_response"
+	"go.uber.org/zap/zapcore"
+)
+
+type logLevelRequest struct {
+	Level      string            `json:"level"`
+	Components map[string]string `json:"components"` // empty level resets the override
+}
+
+type logLevelResponse struct {
+	Level      string            `json:"level"`
+	Components map[string]string `json:"components"`
+	Spec       string            `json:"spec"`
+}
+
+func currentLogLevels() logLevelResponse {
+	components := map[string]string{}
+	for name, level := range logger.ComponentLevels() {
+		components[name] = logger.LevelName(level)
+	}
+	return logLevelResponse{
+		Level:      logger.LevelName(logger.GetLevel()),
+		Components: components,
+		Spec:       logger.LevelSpec(),
+	}
+}
+
+// LogLevelHandler reads (GET) and changes (PUT/POST) log levels at runtime.
+// Mount it behind AuthJWT, e.g. admin.Any("/log-level", middleware.LogLevelHandler()).
+//
+//	PUT {"level": "debug", "components": {"db": "debug", "http": ""}}
+func LogLevelHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == "GET" {
+			httpResponse.Success(c, "successGet", currentLogLevels())
+			return
+		}
+
+		ctx := c.Request.Context()
+		var req logLevelRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			httpResponse.FromAppError(c, errors.InvalidBodyCtx(ctx, "log level", err.Error()))
+			return
+		}
+
+		// validate everything before applying anything
+		var global *zapcore.Level
+		if req.Level != "" {
+			level, err := logger.ParseLevel(req.Level)
+			if err != nil {
+				httpResponse.FromAppError(c, errors.InvalidOptionErrorCtx(ctx, "level", err.Error()))
+				return
+			}
+			global = &level
+		}
+		components := map[string]*zapcore.Level{}
+		for name, value := range req.Components {
+			if value == "" {
+				components[name] = nil
+				continue
+			}
+			level, err := logger.ParseLevel(value)
+			if err != nil {
+				httpResponse.FromAppError(c, errors.InvalidOptionErrorCtx(ctx, "components."+name, err.Error()))
+				return
+			}
+			components[name] = &level
+		}
+
+		if global != nil {
+			logger.SetLevel(*global)
+		}
+		for name, level := range components {
+			if level == nil {
+				logger.ResetComponentLevel(name)
+			} else {
+				logger.SetComponentLevel(name, *level)
+			}
+		}
+
+		logger.WarnCtx(ctx, "Log levels changed", map[string]any{"spec": logger.LevelSpec()})
+		httpResponse.Updated(c, "successUpdate", currentLogLevels())
+	}
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/ginanjar-template-golang/shared-pkg/logger"
-	"github.com/ginanjar-template-golang/shared-pkg/translator"
 	"github.com/ginanjar-template-golang/shared-pkg/utils"
 )
 
@@ -37,13 +36,25 @@ func RequestLogger() gin.HandlerFunc {
 			reqID = utils.NewRequestID()
 		}
 		c.Set("request_id", reqID)
-		utils.SetRequestID(reqID)
 
 		lang := c.GetHeader("Accept-Language")
 		if lang == "" {
 			lang = "en"
 		}
-		translator.InitGlobalTranslator(lang)
+
+		// request-scoped values, never shared between concurrent requests
+		ctx := utils.WithRequestContext(c.Request.Context(), &utils.RequestContext{
+			RequestID: reqID,
+			Lang:      lang,
+			StartTime: start,
+		})
+		// bind once, handlers and error constructors log through it
+		ctx = logger.WithContext(ctx, logger.FromContext(ctx).Component("http").With(
+			"request_id", reqID,
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+		))
+		c.Request = c.Request.WithContext(ctx)
 
 		var bodyData map[string]any
 		if c.Request.Body != nil {
@@ -60,23 +71,19 @@ func RequestLogger() gin.HandlerFunc {
 			queryParams[k] = v
 		}
 
-		logger.Trace("Request Start", map[string]any{
-			"request_id": reqID,
-			"method":     c.Request.Method,
-			"path":       c.Request.URL.Path,
-			"client_ip":  c.ClientIP(),
-			"lang":       lang,
-			"query":      queryParams,
-			"body":       bodyData,
-			"headers":    sanitizeHeaders(c),
+		logger.TraceCtx(ctx, "Request Start", map[string]any{
+			"client_ip": c.ClientIP(),
+			"lang":      lang,
+			"query":     queryParams,
+			"body":      bodyData,
+			"headers":   sanitizeHeaders(c),
 		})
 
 		c.Next()
 
 		duration := time.Since(start)
 
-		logger.Trace("Request End", map[string]any{
-			"request_id": reqID,
+		logger.TraceCtx(ctx, "Request End", map[string]any{
 			"status":     c.Writer.Status(),
 			"latency_ms": duration.Milliseconds(),
 		})
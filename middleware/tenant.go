@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ginanjar-template-golang/shared-pkg/errors"
+	"github.com/ginanjar-template-golang/shared-pkg/logger"
+	httpResponse "github.com/ginanjar-template-golang/shared-pkg/response/http_response"
+	"github.com/ginanjar-template-golang/shared-pkg/utils"
+)
+
+type TenantConfig struct {
+	Header   string // default "X-Tenant-ID"
+	Claim    string // JWT claim, default "tenant_id"
+	Required bool   // reject requests without a tenant (Forbidden)
+
+	// TrustHeader takes the tenant from Header when the token has no claim.
+	// The header is client controlled, enable it only behind a gateway that
+	// sets it after authenticating the caller.
+	TrustHeader bool
+}
+
+// Tenant stores the caller's tenant ID, taken from the verified JWT claim, in
+// the request context where repositories pick it up (see
+// repository.Repository.TenantColumn). A header naming another tenant is
+// rejected with Forbidden. Register it after RequestLogger and AuthJWT.
+//
+//	r.Use(middleware.RequestLogger(), middleware.AuthJWT(jwtCfg), middleware.Tenant(middleware.TenantConfig{Required: true}))
+func Tenant(cfg TenantConfig) gin.HandlerFunc {
+	if cfg.Header == "" {
+		cfg.Header = "X-Tenant-ID"
+	}
+	if cfg.Claim == "" {
+		cfg.Claim = "tenant_id"
+	}
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		claimed := utils.TenantIDFromClaims(utils.UserClaimsFromContext(ctx), cfg.Claim)
+		requested := c.GetHeader(cfg.Header)
+
+		if claimed != "" && requested != "" && claimed != requested {
+			logger.WarnCtx(ctx, "Tenant header does not match token", map[string]any{
+				"path": c.Request.URL.Path,
+			})
+
+			httpResponse.FromAppError(c, errors.ForbiddenCtx(ctx, nil))
+			c.Abort()
+			return
+		}
+
+		tenantID := claimed
+		if tenantID == "" && cfg.TrustHeader {
+			tenantID = requested
+		}
+		if tenantID == "" {
+			if cfg.Required {
+				logger.WarnCtx(ctx, "Missing tenant", map[string]any{
+					"path": c.Request.URL.Path,
+				})
+
+				httpResponse.FromAppError(c, errors.ForbiddenCtx(ctx, nil))
+				c.Abort()
+				return
+			}
+			c.Next()
+			return
+		}
+
+		c.Set("tenant_id", tenantID)
+		ctx = utils.WithTenantID(ctx, tenantID)
+		ctx = logger.WithContext(ctx, logger.FromContext(ctx).With("tenant_id", tenantID))
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ginanjar-template-golang/shared-pkg/utils"
+)
+
+func TestTenant(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        TenantConfig
+		claim      string
+		header     string
+		wantStatus int
+		wantTenant string
+	}{
+		{"claim", TenantConfig{Required: true}, "a", "", http.StatusOK, "a"},
+		{"claim matching header", TenantConfig{Required: true}, "a", "a", http.StatusOK, "a"},
+		{"header naming another tenant", TenantConfig{}, "a", "b", http.StatusForbidden, ""},
+		{"untrusted header is ignored", TenantConfig{}, "", "b", http.StatusOK, ""},
+		{"untrusted header does not satisfy Required", TenantConfig{Required: true}, "", "b", http.StatusForbidden, ""},
+		{"trusted header", TenantConfig{Required: true, TrustHeader: true}, "", "b", http.StatusOK, "b"},
+		{"missing tenant", TenantConfig{Required: true, TrustHeader: true}, "", "", http.StatusForbidden, ""},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotTenant string
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				if tt.claim != "" {
+					ctx := utils.WithUserClaims(c.Request.Context(), map[string]any{"tenant_id": tt.claim})
+					c.Request = c.Request.WithContext(ctx)
+				}
+			}, Tenant(tt.cfg))
+			r.GET("/notes", func(c *gin.Context) {
+				gotTenant = utils.TenantIDFromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/notes", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-ID", tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if gotTenant != tt.wantTenant {
+				t.Fatalf("tenant = %q, want %q", gotTenant, tt.wantTenant)
+			}
+		})
+	}
+}
@@ -88,6 +88,9 @@ type Pagination struct {
 	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
 	TotalRow      int32                  `protobuf:"varint,4,opt,name=total_row,json=totalRow,proto3" json:"total_row,omitempty"`
 	Results       []byte                 `protobuf:"bytes,5,opt,name=results,proto3" json:"results,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,6,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	PrevCursor    string                 `protobuf:"bytes,7,opt,name=prev_cursor,json=prevCursor,proto3" json:"prev_cursor,omitempty"`
+	HasMore       bool                   `protobuf:"varint,8,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -157,6 +160,27 @@ func (x *Pagination) GetResults() []byte {
 	return nil
 }
 
+func (x *Pagination) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *Pagination) GetPrevCursor() string {
+	if x != nil {
+		return x.PrevCursor
+	}
+	return ""
+}
+
+func (x *Pagination) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
 type StandardResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Meta          *Meta                  `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
@@ -331,14 +355,19 @@ const file_response_proto_rawDesc = "" +
 	"\n" +
 	"request_id\x18\x01 \x01(\tR\trequestId\x12\x1b\n" +
 	"\thttp_code\x18\x02 \x01(\x05R\bhttpCode\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage\"\x81\x01\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\xde\x01\n" +
 	"\n" +
 	"Pagination\x12\x12\n" +
 	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x12\n" +
 	"\x04size\x18\x02 \x01(\x05R\x04size\x12\x14\n" +
 	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x1b\n" +
 	"\ttotal_row\x18\x04 \x01(\x05R\btotalRow\x12\x18\n" +
-	"\aresults\x18\x05 \x01(\fR\aresults\"R\n" +
+	"\aresults\x18\x05 \x01(\fR\aresults\x12\x1f\n" +
+	"\vnext_cursor\x18\x06 \x01(\tR\n" +
+	"nextCursor\x12\x1f\n" +
+	"\vprev_cursor\x18\a \x01(\tR\n" +
+	"prevCursor\x12\x19\n" +
+	"\bhas_more\x18\b \x01(\bR\ahasMore\"R\n" +
 	"\x10StandardResponse\x12$\n" +
 	"\x04meta\x18\x01 \x01(\v2\x10.responsepb.MetaR\x04meta\x12\x18\n" +
 	"\aresults\x18\x02 \x01(\fR\aresults\"\x8c\x01\n" +
@@ -0,0 +1,419 @@
+// Package query binds list parameters (page, limit, sort, filter[...]) from
+// Gin or gRPC requests into repository page queries. It lives apart from
+// validator so input validation does not pull in gorm.
+package query
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ginanjar-template-golang/shared-pkg/db/gorm/repository"
+	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
+	"github.com/ginanjar-template-golang/shared-pkg/validator"
+)
+
+// =======================================================
+// LIST QUERY BINDING (page, limit, sort, filter[...])
+// =======================================================
+
+// listSpec is the parsed form of a list spec struct:
+//
+//	type UserListQuery struct {
+//		_         struct{}  `paginate:"max=50,default=20,sort=-created_at"`
+//		Status    string    `query:"status" filter:"eq,in"`
+//		Name      string    `query:"name" column:"full_name" filter:"like" sort:"true"`
+//		Age       int       `query:"age" filter:"range"`
+//		DeletedAt time.Time `query:"deleted_at" filter:"is_null"`
+//		CreatedAt time.Time `query:"created_at" sort:"true"`
+//	}
+type listSpec struct {
+	maxLimit     int
+	defaultLimit int
+	defaultSort  string
+	fields       map[string]specField
+	sorts        map[string]string
+	filters      map[string]string
+}
+
+type specField struct {
+	typ reflect.Type
+	ops map[repository.FilterOp]bool
+}
+
+var specs sync.Map // reflect.Type -> *listSpec
+
+// operators accepted as filter[field][op]; gte / lte need "range" in the filter tag
+var filterOps = map[string]repository.FilterOp{
+	"eq":      repository.FilterEq,
+	"in":      repository.FilterIn,
+	"like":    repository.FilterLike,
+	"gte":     repository.FilterRange,
+	"lte":     repository.FilterRange,
+	"is_null": repository.FilterIsNull,
+}
+
+// BindPage parses ?page=&limit=&sort=-created_at,name&filter[status][in]=a,b
+// from c against the spec struct (see listSpec) and returns a PageQuery whose
+// whitelists come from the spec. Invalid values, a limit above the max and
+// fields not declared in spec are reported as a validationFailed AppError.
+//
+//	q, appErr := query.BindPage(c, UserListQuery{})
+//	if appErr != nil {
+//		httpResponse.FromAppError(c, appErr)
+//		return
+//	}
+//	page, err := userRepo.FindPageCtx(c.Request.Context(), q)
+func BindPage(c *gin.Context, spec any) (repository.PageQuery, *appError.AppError) {
+	return bindPageQuery(c.Request.Context(), c.Request.URL.Query(), spec)
+}
+
+// BindCursor is BindPage for keyset pages, reading ?cursor= instead of ?page=;
+// a page parameter is ignored.
+func BindCursor(c *gin.Context, spec any) (repository.CursorQuery, *appError.AppError) {
+	ctx := c.Request.Context()
+	values := c.Request.URL.Query()
+	errMap := make(map[string]string)
+	q := bindListQuery(ctx, values, getSpec(spec), errMap)
+	return repository.CursorQuery{
+		Cursor:         values.Get("cursor"),
+		Limit:          q.Limit,
+		Sort:           q.Sort,
+		Filters:        q.Filters,
+		AllowedSorts:   q.AllowedSorts,
+		AllowedFilters: q.AllowedFilters,
+	}, validationError(ctx, errMap)
+}
+
+// GrpcPageRequest is implemented by authpb.PaginationRequest and any request message with page and limit.
+type GrpcPageRequest interface {
+	GetPage() int32
+	GetLimit() int32
+}
+
+// BindGrpcPage builds a PageQuery from a gRPC request. Messages that also
+// expose GetSort() string and GetFilter() map[string]string get sorting and
+// filtering with the same syntax as the query string (filter keys like "status" or "age][gte").
+func BindGrpcPage(ctx context.Context, req GrpcPageRequest, spec any) (repository.PageQuery, *appError.AppError) {
+	values := url.Values{}
+	if req.GetPage() != 0 {
+		values.Set("page", strconv.Itoa(int(req.GetPage())))
+	}
+	if req.GetLimit() != 0 {
+		values.Set("limit", strconv.Itoa(int(req.GetLimit())))
+	}
+	if r, ok := req.(interface{ GetSort() string }); ok && r.GetSort() != "" {
+		values.Set("sort", r.GetSort())
+	}
+	if r, ok := req.(interface{ GetFilter() map[string]string }); ok {
+		for key, value := range r.GetFilter() {
+			values.Set("filter["+key+"]", value)
+		}
+	}
+	return bindPageQuery(ctx, values, spec)
+}
+
+func bindPageQuery(ctx context.Context, values url.Values, spec any) (repository.PageQuery, *appError.AppError) {
+	errMap := make(map[string]string)
+	q := bindListQuery(ctx, values, getSpec(spec), errMap)
+	q.Page = 1
+
+	if raw := values.Get("page"); raw != "" {
+		page, err := strconv.Atoi(raw)
+		switch {
+		case err != nil:
+			errMap["page"] = validator.FieldMessage(ctx, "page", "number", "")
+		case page < 1:
+			errMap["page"] = validator.FieldMessage(ctx, "page", "min", "1")
+		case repository.MaxPage > 0 && page > repository.MaxPage:
+			errMap["page"] = validator.FieldMessage(ctx, "page", "max", strconv.Itoa(repository.MaxPage))
+		default:
+			q.Page = page
+		}
+	}
+	return q, validationError(ctx, errMap)
+}
+
+// bindListQuery parses limit, sort and filter[...] shared by page and cursor
+// queries. Every invalid parameter gets its own errMap entry: "limit",
+// "sort[field]" and the filter key as sent, e.g. "filter[age][gte]".
+func bindListQuery(ctx context.Context, values url.Values, s *listSpec, errMap map[string]string) repository.PageQuery {
+	q := repository.PageQuery{
+		Limit:          s.defaultLimit,
+		AllowedSorts:   s.sorts,
+		AllowedFilters: s.filters,
+	}
+
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		switch {
+		case err != nil:
+			errMap["limit"] = validator.FieldMessage(ctx, "limit", "number", "")
+		case limit < 1:
+			errMap["limit"] = validator.FieldMessage(ctx, "limit", "min", "1")
+		case s.maxLimit > 0 && limit > s.maxLimit:
+			errMap["limit"] = validator.FieldMessage(ctx, "limit", "max", strconv.Itoa(s.maxLimit))
+		default:
+			q.Limit = limit
+		}
+	}
+
+	sort := values.Get("sort")
+	if sort == "" {
+		sort = s.defaultSort
+	}
+	for _, part := range strings.Split(sort, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		field := parseSortField(part)
+		if _, ok := s.sorts[field.Field]; !ok {
+			errMap["sort["+field.Field+"]"] = validator.FieldMessage(ctx, field.Field, "sortNotAllowed", "")
+			continue
+		}
+		q.Sort = append(q.Sort, field)
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		name, op, ok := parseFilterKey(key)
+		if !ok || values.Get(key) == "" {
+			continue
+		}
+		filter, msgTag, param := s.filter(name, op, values.Get(key))
+		if msgTag != "" {
+			errMap[key] = validator.FieldMessage(ctx, name, msgTag, param)
+			continue
+		}
+		q.Filters = mergeFilter(q.Filters, filter)
+	}
+	return q
+}
+
+// validationError returns errMap as a validationFailed AppError, nil when empty.
+func validationError(ctx context.Context, errMap map[string]string) *appError.AppError {
+	if len(errMap) == 0 {
+		return nil
+	}
+	appErr := appError.NewCtx(ctx, "validationFailed", "", errMap)
+	return &appErr
+}
+
+// parseSortField parses "name" or "-name" (descending).
+func parseSortField(raw string) repository.SortField {
+	if strings.HasPrefix(raw, "-") {
+		return repository.SortField{Field: raw[1:], Desc: true}
+	}
+	return repository.SortField{Field: strings.TrimPrefix(raw, "+")}
+}
+
+// parseFilterKey splits filter[name] and filter[name][op].
+func parseFilterKey(key string) (name, op string, ok bool) {
+	if !strings.HasPrefix(key, "filter[") || !strings.HasSuffix(key, "]") {
+		return "", "", false
+	}
+	inner := strings.TrimSuffix(strings.TrimPrefix(key, "filter["), "]")
+	name, op, _ = strings.Cut(inner, "][")
+	if op == "" {
+		op = "eq"
+	}
+	return name, op, name != ""
+}
+
+// filter converts one raw value, returning a validation message tag on failure.
+func (s *listSpec) filter(name, op, raw string) (repository.Filter, string, string) {
+	field, ok := s.fields[name]
+	if !ok || len(field.ops) == 0 {
+		return repository.Filter{}, "filterNotAllowed", ""
+	}
+	filterOp, known := filterOps[op]
+	if !known || !field.ops[filterOp] {
+		return repository.Filter{}, "filterOpNotAllowed", op
+	}
+
+	f := repository.Filter{Field: name, Op: filterOp}
+	switch op {
+	case "in":
+		parts := strings.Split(raw, ",")
+		values := make([]any, 0, len(parts))
+		for _, part := range parts {
+			v, tag := convertQueryValue(strings.TrimSpace(part), field.typ)
+			if tag != "" {
+				return f, tag, ""
+			}
+			values = append(values, v)
+		}
+		f.Value = values
+	case "like":
+		f.Value = raw
+	case "is_null":
+		isNull, err := strconv.ParseBool(raw)
+		if err != nil {
+			return f, "boolean", ""
+		}
+		f.Value = isNull
+	default:
+		v, tag := convertQueryValue(raw, field.typ)
+		if tag != "" {
+			return f, tag, ""
+		}
+		switch op {
+		case "gte":
+			f.From = v
+		case "lte":
+			f.To = v
+		default:
+			f.Value = v
+		}
+	}
+	return f, "", ""
+}
+
+// mergeFilter joins filter[x][gte] and filter[x][lte] into one range filter.
+func mergeFilter(filters []repository.Filter, f repository.Filter) []repository.Filter {
+	if f.Op == repository.FilterRange {
+		for i := range filters {
+			if filters[i].Field == f.Field && filters[i].Op == repository.FilterRange {
+				if f.From != nil {
+					filters[i].From = f.From
+				}
+				if f.To != nil {
+					filters[i].To = f.To
+				}
+				return filters
+			}
+		}
+	}
+	return append(filters, f)
+}
+
+// convertQueryValue parses raw into typ, returning the validation message tag on failure.
+func convertQueryValue(raw string, typ reflect.Type) (any, string) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == reflect.TypeOf(time.Time{}) {
+		for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+			if t, err := time.Parse(layout, raw); err == nil {
+				return t, ""
+			}
+		}
+		return nil, "datetime"
+	}
+
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, "number"
+		}
+		return v, ""
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return nil, "number"
+		}
+		return v, ""
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, "number"
+		}
+		return v, ""
+	case reflect.Bool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, "boolean"
+		}
+		return v, ""
+	default:
+		return raw, ""
+	}
+}
+
+// getSpec parses and caches the tags of spec's type.
+func getSpec(spec any) *listSpec {
+	typ := reflect.TypeOf(spec)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("query: list query spec must be a struct, got %T", spec))
+	}
+	if cached, ok := specs.Load(typ); ok {
+		return cached.(*listSpec)
+	}
+
+	s := &listSpec{
+		maxLimit:     repository.MaxPageLimit,
+		defaultLimit: repository.DefaultPageLimit,
+		fields:       map[string]specField{},
+		sorts:        map[string]string{},
+		filters:      map[string]string{},
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if tag, ok := sf.Tag.Lookup("paginate"); ok {
+			s.parsePaginateTag(tag)
+			continue
+		}
+		name := sf.Tag.Get("query")
+		if name == "" || name == "-" {
+			continue
+		}
+		column := sf.Tag.Get("column")
+		if column == "" {
+			column = name
+		}
+
+		field := specField{typ: sf.Type, ops: map[repository.FilterOp]bool{}}
+		for _, op := range strings.Split(sf.Tag.Get("filter"), ",") {
+			if op = strings.TrimSpace(op); op != "" {
+				field.ops[repository.FilterOp(op)] = true
+			}
+		}
+		if len(field.ops) > 0 {
+			s.filters[name] = column
+		}
+		if sortable, _ := strconv.ParseBool(sf.Tag.Get("sort")); sortable {
+			s.sorts[name] = column
+		}
+		s.fields[name] = field
+	}
+
+	cached, _ := specs.LoadOrStore(typ, s)
+	return cached.(*listSpec)
+}
+
+// parsePaginateTag reads `paginate:"max=50,default=20,sort=-created_at|name"`.
+func (s *listSpec) parsePaginateTag(tag string) {
+	for _, part := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "max":
+			s.maxLimit, _ = strconv.Atoi(value)
+		case "default":
+			s.defaultLimit, _ = strconv.Atoi(value)
+		case "sort":
+			s.defaultSort = strings.ReplaceAll(value, "|", ",")
+		}
+	}
+	if s.defaultLimit <= 0 {
+		s.defaultLimit = repository.DefaultPageLimit
+	}
+	if s.maxLimit > 0 && s.defaultLimit > s.maxLimit {
+		s.defaultLimit = s.maxLimit
+	}
+}
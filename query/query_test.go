@@ -0,0 +1,176 @@
+package query
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ginanjar-template-golang/shared-pkg/db/gorm/repository"
+	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
+)
+
+type userListQuery struct {
+	_         struct{}  `paginate:"max=50,default=20,sort=-created_at"`
+	Status    string    `query:"status" filter:"eq,in"`
+	Name      string    `query:"name" column:"full_name" filter:"like" sort:"true"`
+	Age       int       `query:"age" filter:"range"`
+	DeletedAt time.Time `query:"deleted_at" filter:"is_null"`
+	CreatedAt time.Time `query:"created_at" sort:"true"`
+}
+
+func ginContext(target string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", target, nil)
+	return c
+}
+
+func TestBindPage(t *testing.T) {
+	c := ginContext("/users?page=2&limit=30&sort=-created_at,name" +
+		"&filter[status][in]=active,pending&filter[age][gte]=18&filter[age][lte]=30" +
+		"&filter[name][like]=jo&filter[deleted_at][is_null]=true")
+
+	q, appErr := BindPage(c, userListQuery{})
+	if appErr != nil {
+		t.Fatalf("BindPage: %v (%v)", appErr, appErr.Data)
+	}
+	if q.Page != 2 || q.Limit != 30 {
+		t.Fatalf("page, limit = %d, %d, want 2, 30", q.Page, q.Limit)
+	}
+
+	wantSort := []repository.SortField{{Field: "created_at", Desc: true}, {Field: "name"}}
+	if !reflect.DeepEqual(q.Sort, wantSort) {
+		t.Fatalf("sort = %+v, want %+v", q.Sort, wantSort)
+	}
+	if q.AllowedSorts["name"] != "full_name" || q.AllowedFilters["name"] != "full_name" {
+		t.Fatalf("whitelists = %v / %v, want name mapped to full_name", q.AllowedSorts, q.AllowedFilters)
+	}
+
+	wantFilters := []repository.Filter{
+		{Field: "age", Op: repository.FilterRange, From: int64(18), To: int64(30)},
+		{Field: "deleted_at", Op: repository.FilterIsNull, Value: true},
+		{Field: "name", Op: repository.FilterLike, Value: "jo"},
+		{Field: "status", Op: repository.FilterIn, Value: []any{"active", "pending"}},
+	}
+	if !reflect.DeepEqual(q.Filters, wantFilters) {
+		t.Fatalf("filters = %+v, want %+v", q.Filters, wantFilters)
+	}
+}
+
+func TestBindPageDefaults(t *testing.T) {
+	q, appErr := BindPage(ginContext("/users"), userListQuery{})
+	if appErr != nil {
+		t.Fatalf("BindPage: %v", appErr)
+	}
+	if q.Page != 1 || q.Limit != 20 {
+		t.Fatalf("page, limit = %d, %d, want 1, 20", q.Page, q.Limit)
+	}
+	if want := []repository.SortField{{Field: "created_at", Desc: true}}; !reflect.DeepEqual(q.Sort, want) {
+		t.Fatalf("sort = %+v, want %+v", q.Sort, want)
+	}
+}
+
+func TestBindPageInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		field  string
+	}{
+		{"page not a number", "/users?page=abc", "page"},
+		{"page below one", "/users?page=0", "page"},
+		{"page above max", "/users?page=99999999999", "page"},
+		{"limit above max", "/users?limit=51", "limit"},
+		{"sort not allowed", "/users?sort=age", "sort[age]"},
+		{"filter not declared", "/users?filter[email]=a", "filter[email]"},
+		{"operator not allowed", "/users?filter[status][like]=a", "filter[status][like]"},
+		{"bad number", "/users?filter[age][gte]=old", "filter[age][gte]"},
+		{"bad boolean", "/users?filter[deleted_at][is_null]=maybe", "filter[deleted_at][is_null]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, appErr := BindPage(ginContext(tt.target), userListQuery{})
+			assertValidationField(t, appErr, tt.field)
+		})
+	}
+}
+
+func TestBindPageAggregatesErrors(t *testing.T) {
+	c := ginContext("/users?sort=age,-status,name&filter[age][gte]=old&filter[age][lte]=young&filter[email]=a")
+	_, appErr := BindPage(c, userListQuery{})
+	for _, field := range []string{"sort[age]", "sort[status]", "filter[age][gte]", "filter[age][lte]", "filter[email]"} {
+		assertValidationField(t, appErr, field)
+	}
+	if errMap := appErr.Data.(map[string]string); len(errMap) != 5 {
+		t.Fatalf("errors = %v, want 5 entries", errMap)
+	}
+}
+
+func TestBindCursor(t *testing.T) {
+	c := ginContext("/users?cursor=abc&page=oops&limit=5&sort=name&filter[status]=active")
+	q, appErr := BindCursor(c, userListQuery{})
+	if appErr != nil {
+		t.Fatalf("BindCursor: %v (page must be ignored)", appErr.Data)
+	}
+	if q.Cursor != "abc" || q.Limit != 5 || len(q.Sort) != 1 || len(q.Filters) != 1 {
+		t.Fatalf("query = %+v, want cursor abc, limit 5, one sort and one filter", q)
+	}
+
+	_, appErr = BindCursor(ginContext("/users?page=99999999999&sort=age&limit=51"), userListQuery{})
+	assertValidationField(t, appErr, "sort[age]")
+	assertValidationField(t, appErr, "limit")
+	if _, ok := appErr.Data.(map[string]string)["page"]; ok {
+		t.Fatalf("errors = %v, want no page entry", appErr.Data)
+	}
+}
+
+type pageRequest struct {
+	page, limit int32
+	sort        string
+	filter      map[string]string
+}
+
+func (r pageRequest) GetPage() int32               { return r.page }
+func (r pageRequest) GetLimit() int32              { return r.limit }
+func (r pageRequest) GetSort() string              { return r.sort }
+func (r pageRequest) GetFilter() map[string]string { return r.filter }
+
+func TestBindGrpcPage(t *testing.T) {
+	req := pageRequest{page: 3, limit: 10, sort: "name", filter: map[string]string{"status": "active", "age][lte": "40"}}
+	q, appErr := BindGrpcPage(context.Background(), req, userListQuery{})
+	if appErr != nil {
+		t.Fatalf("BindGrpcPage: %v (%v)", appErr, appErr.Data)
+	}
+	if q.Page != 3 || q.Limit != 10 {
+		t.Fatalf("page, limit = %d, %d, want 3, 10", q.Page, q.Limit)
+	}
+	if want := []repository.SortField{{Field: "name"}}; !reflect.DeepEqual(q.Sort, want) {
+		t.Fatalf("sort = %+v, want %+v", q.Sort, want)
+	}
+	wantFilters := []repository.Filter{
+		{Field: "age", Op: repository.FilterRange, To: int64(40)},
+		{Field: "status", Op: repository.FilterEq, Value: "active"},
+	}
+	if !reflect.DeepEqual(q.Filters, wantFilters) {
+		t.Fatalf("filters = %+v, want %+v", q.Filters, wantFilters)
+	}
+
+	_, appErr = BindGrpcPage(context.Background(), pageRequest{limit: 500}, userListQuery{})
+	assertValidationField(t, appErr, "limit")
+}
+
+func assertValidationField(t *testing.T, appErr *appError.AppError, field string) {
+	t.Helper()
+	if appErr == nil {
+		t.Fatalf("want validationFailed for %s, got nil", field)
+	}
+	if appErr.MessageKey != "validationFailed" {
+		t.Fatalf("error key = %q, want validationFailed", appErr.MessageKey)
+	}
+	errMap, _ := appErr.Data.(map[string]string)
+	if _, ok := errMap[field]; !ok {
+		t.Fatalf("errors = %v, want an entry for %s", errMap, field)
+	}
+}
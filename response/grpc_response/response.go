@@ -4,9 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
 
 	errHandler "github.com/ginanjar-template-golang/shared-pkg/errors"
 	"github.com/ginanjar-template-golang/shared-pkg/logger"
@@ -40,9 +46,9 @@ func Deleted(ctx context.Context, messageKey string) (*responsepb.StandardRespon
 }
 
 // baseResponse helper untuk response sukses standar
-func baseResponse(_ context.Context, httpCode int32, grpcCode codes.Code, messageKey string, data any) (*responsepb.StandardResponse, error) {
-	reqID := utils.GetRequestID()
-	message := translator.GetMessageGlobal(messageKey)
+func baseResponse(ctx context.Context, httpCode int32, grpcCode codes.Code, messageKey string, data any) (*responsepb.StandardResponse, error) {
+	reqID := utils.RequestIDFromContext(ctx)
+	message := translator.GetMessageCtx(ctx, messageKey)
 
 	var jsonBytes []byte
 	if data != nil {
@@ -51,12 +57,11 @@ func baseResponse(_ context.Context, httpCode int32, grpcCode codes.Code, messag
 
 	decode, _ := utils.DecodeBytesToJSON(jsonBytes)
 
-	logger.Info("Success", map[string]any{
-		"request_id": reqID,
-		"http_code":  httpCode,
-		"grpc_code":  grpcCode,
-		"message":    message,
-		"results":    decode,
+	logger.InfoCtx(ctx, "Success", map[string]any{
+		"http_code": httpCode,
+		"grpc_code": grpcCode,
+		"message":   message,
+		"results":   decode,
 	})
 
 	return &responsepb.StandardResponse{
@@ -79,23 +84,42 @@ type PaginationData struct {
 	Limit    int32
 	TotalRow int32
 	Results  any `json:"results,omitempty"`
+
+	// keyset pagination, see CursorPaginationSuccess
+	NextCursor string
+	PrevCursor string
+	HasMore    bool
 }
 
 func PaginationSuccess(ctx context.Context, messageKey string, pagination PaginationData) (*responsepb.PaginationResponse, error) {
-	reqID := utils.GetRequestID()
-	message := translator.GetMessageGlobal(messageKey)
+	return paginationSuccess(ctx, messageKey, pagination, "GRPC Pagination Success", map[string]any{
+		"page":      pagination.Page,
+		"limit":     pagination.Limit,
+		"total_row": pagination.TotalRow,
+	})
+}
+
+// CursorPaginationSuccess returns a keyset page: page and total_row stay 0,
+// clients follow next_cursor / prev_cursor while has_more is true.
+func CursorPaginationSuccess(ctx context.Context, messageKey string, pagination PaginationData) (*responsepb.PaginationResponse, error) {
+	return paginationSuccess(ctx, messageKey, pagination, "GRPC Cursor Pagination Success", map[string]any{
+		"limit":       pagination.Limit,
+		"has_more":    pagination.HasMore,
+		"next_cursor": pagination.NextCursor != "",
+		"prev_cursor": pagination.PrevCursor != "",
+	})
+}
+
+func paginationSuccess(ctx context.Context, messageKey string, pagination PaginationData, logMsg string, fields map[string]any) (*responsepb.PaginationResponse, error) {
+	reqID := utils.RequestIDFromContext(ctx)
+	message := translator.GetMessageCtx(ctx, messageKey)
 
 	resultsJSON, _ := json.Marshal(pagination.Results)
 	decode, _ := utils.DecodeBytesToJSON(resultsJSON)
 
-	logger.Info("GRPC Pagination Success", map[string]any{
-		"request_id": reqID,
-		"page":       pagination.Page,
-		"limit":      pagination.Limit,
-		"total_row":  pagination.TotalRow,
-		"message":    message,
-		"results":    decode,
-	})
+	fields["message"] = message
+	fields["results"] = decode
+	logger.InfoCtx(ctx, logMsg, fields)
 
 	return &responsepb.PaginationResponse{
 		Meta: &responsepb.Meta{
@@ -104,10 +128,13 @@ func PaginationSuccess(ctx context.Context, messageKey string, pagination Pagina
 			Message:   message,
 		},
 		Pagination: &responsepb.Pagination{
-			Page:     pagination.Page,
-			Size:     pagination.Size,
-			Limit:    pagination.Limit,
-			TotalRow: pagination.TotalRow,
+			Page:       pagination.Page,
+			Size:       pagination.Size,
+			Limit:      pagination.Limit,
+			TotalRow:   pagination.TotalRow,
+			NextCursor: pagination.NextCursor,
+			PrevCursor: pagination.PrevCursor,
+			HasMore:    pagination.HasMore,
 		},
 		Results: resultsJSON,
 	}, nil
@@ -117,22 +144,125 @@ func PaginationSuccess(ctx context.Context, messageKey string, pagination Pagina
 // ERROR RESPONSE
 // =========================
 
+// InternalCodeTrailer is the trailer key carrying AppError.InternalCode
+const InternalCodeTrailer = "x-internal-code"
+
+// ErrorDomain is sent as errdetails.ErrorInfo.Domain, usually the service name
+var ErrorDomain = "shared-pkg"
+
+// FromAppError converts err into a gRPC status carrying the same information as the
+// HTTP ResponseError: ErrorInfo (message key, internal code, data), BadRequest field
+// violations for validation errors, RequestInfo and the translated LocalizedMessage.
+// The AppError Cause is never attached.
 func FromAppError(ctx context.Context, err error) error {
-	reqID := utils.GetRequestID()
-
-	var appErr errHandler.AppError
-	switch e := err.(type) {
-	case errHandler.AppError:
-		appErr = e
-	case *errHandler.AppError:
-		appErr = *e
+	reqID := utils.RequestIDFromContext(ctx)
+
+	appErr, ok := errHandler.FromError(err)
+	if !ok {
+		msg := withRequestID(reqID, fmt.Sprintf("Unexpected error: %v", err))
+		if reqID == "" {
+			return status.Error(codes.Internal, msg)
+		}
+		st, detailErr := status.New(codes.Internal, msg).WithDetails(&errdetails.RequestInfo{RequestId: reqID})
+		if detailErr != nil {
+			return status.Error(codes.Internal, msg)
+		}
+		return st.Err()
+	}
+
+	// internal code travels in the trailer, read it client side with grpc.Trailer(&md)
+	if appErr.InternalCode != 0 {
+		_ = grpc.SetTrailer(ctx, metadata.Pairs(InternalCodeTrailer, strconv.Itoa(appErr.InternalCode)))
+	}
+
+	msg := translator.GetMessageCtx(ctx, appErr.MessageKey)
+	fullMsg := withRequestID(reqID, msg)
+
+	st, detailErr := status.New(appErr.GrpcCode, fullMsg).WithDetails(errorDetails(ctx, appErr, reqID, msg)...)
+	if detailErr != nil {
+		logger.WarnCtx(ctx, "Failed to attach gRPC error details", nil, detailErr)
+		return status.Error(appErr.GrpcCode, fullMsg)
+	}
+	return st.Err()
+}
+
+func errorDetails(ctx context.Context, appErr errHandler.AppError, reqID, msg string) []protoadapt.MessageV1 {
+	info := &errdetails.ErrorInfo{
+		Reason: appErr.MessageKey,
+		Domain: ErrorDomain,
+		Metadata: map[string]string{
+			"message_key":   appErr.MessageKey,
+			"internal_code": strconv.Itoa(appErr.InternalCode),
+			"http_code":     strconv.Itoa(appErr.HttpCode),
+		},
+	}
+
+	lang := utils.LangFromContext(ctx)
+	if lang == "" {
+		lang = "en"
+	}
+
+	details := []protoadapt.MessageV1{info}
+	if reqID != "" {
+		details = append(details, &errdetails.RequestInfo{RequestId: reqID})
+	}
+	details = append(details, &errdetails.LocalizedMessage{Locale: lang, Message: msg})
+
+	// stack is exposed to clients only when errors.SetStackExposure is on
+	if stack := appErr.StackFrames(); len(stack) > 0 && errHandler.StackExposed() {
+		entries := make([]string, 0, len(stack))
+		for _, f := range stack {
+			entries = append(entries, fmt.Sprintf("%s\n\t%s:%d", f.Function, f.File, f.Line))
+		}
+		details = append(details, &errdetails.DebugInfo{StackEntries: entries})
+	}
+
+	if violations := fieldViolations(appErr.Data); len(violations) > 0 {
+		details = append(details, &errdetails.BadRequest{FieldViolations: violations})
+	} else if appErr.Data != nil {
+		if b, err := json.Marshal(appErr.Data); err == nil {
+			info.Metadata["data"] = string(b)
+		}
+	}
+
+	return details
+}
+
+// withRequestID prefixes msg with "[reqID] " when there is a request ID.
+func withRequestID(reqID, msg string) string {
+	if reqID == "" {
+		return msg
+	}
+	return "[" + reqID + "] " + msg
+}
+
+// fieldViolations converts validator field errors (map of field to message) into BadRequest violations.
+func fieldViolations(data any) []*errdetails.BadRequest_FieldViolation {
+	fields := map[string]string{}
+	switch d := data.(type) {
+	case map[string]string:
+		fields = d
+	case map[string]any:
+		for k, v := range d {
+			str, ok := v.(string)
+			if !ok {
+				return nil
+			}
+			fields[k] = str
+		}
 	default:
-		msg := fmt.Sprintf("[%s] Unexpected error: %v", reqID, err)
-		return status.Error(codes.Internal, msg)
+		return nil
 	}
 
-	msg := translator.GetMessageGlobal(appErr.MessageKey)
-	fullMsg := fmt.Sprintf("[%s] %s", reqID, msg)
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
 
-	return status.Error(appErr.GrpcCode, fullMsg)
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(keys))
+	for _, k := range keys {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: k, Description: fields[k]})
+	}
+	return violations
 }
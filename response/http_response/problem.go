@@ -0,0 +1,118 @@
+package http_response
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+
+	httpCode "github.com/ginanjar-template-golang/shared-pkg/constants/http_code"
+	internalCode "github.com/ginanjar-template-golang/shared-pkg/constants/internal_code"
+	errHandler "github.com/ginanjar-template-golang/shared-pkg/errors"
+	"github.com/ginanjar-template-golang/shared-pkg/translator"
+	"github.com/ginanjar-template-golang/shared-pkg/utils"
+)
+
+// ========================
+// ERROR FORMAT SELECTION
+// ========================
+
+type ErrorFormat int32
+
+const (
+	ErrorFormatEnvelope  ErrorFormat = iota // legacy ResponseError{Meta, Error} (default)
+	ErrorFormatProblem                      // RFC 7807 application/problem+json
+	ErrorFormatNegotiate                    // problem+json only when the Accept header asks for it
+)
+
+const ProblemContentType = "application/problem+json"
+
+var (
+	errorFormat atomic.Int32
+
+	// ProblemTypeBase prefixes the message key to build the problem "type" URI,
+	// e.g. "https://api.example.com/errors/". Empty means "about:blank".
+	ProblemTypeBase = ""
+)
+
+// SetErrorFormat selects how FromAppError renders errors for every request.
+func SetErrorFormat(format ErrorFormat) {
+	errorFormat.Store(int32(format))
+}
+
+func GetErrorFormat() ErrorFormat {
+	return ErrorFormat(errorFormat.Load())
+}
+
+func useProblemFormat(c *gin.Context) bool {
+	switch GetErrorFormat() {
+	case ErrorFormatProblem:
+		return true
+	case ErrorFormatNegotiate:
+		return strings.Contains(c.GetHeader("Accept"), ProblemContentType)
+	default:
+		return false
+	}
+}
+
+// ========================
+// PROBLEM DETAILS (RFC 7807)
+// ========================
+
+type ProblemDetails struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// extension members
+	RequestID    string             `json:"request_id"`
+	InternalCode int                `json:"internal_code,omitempty"`
+	Errors       map[string]string  `json:"errors,omitempty"` // validation field errors
+	Data         any                `json:"data,omitempty"`
+	Stack        []utils.StackFrame `json:"stack,omitempty"` // see errors.SetStackExposure
+}
+
+func problemType(messageKey string) string {
+	if ProblemTypeBase == "" || messageKey == "" {
+		return "about:blank"
+	}
+	return ProblemTypeBase + messageKey
+}
+
+func problemFromError(c *gin.Context, ctx context.Context, reqID string, err error) {
+	problem := ProblemDetails{
+		Instance:  c.Request.URL.Path,
+		RequestID: reqID,
+	}
+
+	// Cause is never serialized, only Data is sent to the client
+	if appErr, ok := errHandler.FromError(err); ok {
+		problem.Type = problemType(appErr.MessageKey)
+		problem.Title = translator.GetMessageCtx(ctx, appErr.MessageKey)
+		problem.Status = appErr.HttpCode
+		problem.InternalCode = appErr.InternalCode
+		problem.Stack = debugStack(appErr)
+
+		switch data := appErr.Data.(type) {
+		case nil:
+		case string:
+			problem.Detail = data
+		case map[string]string:
+			problem.Errors = data
+		default:
+			problem.Data = data
+		}
+	} else {
+		problem.Type = problemType("")
+		problem.Title = "Unexpected error"
+		problem.Status = httpCode.InternalServerError
+		problem.InternalCode = internalCode.UnknownError
+		problem.Detail = err.Error()
+	}
+
+	c.Header("Content-Type", ProblemContentType)
+	c.JSON(problem.Status, problem)
+}
@@ -1,11 +1,13 @@
 package http_response
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	httpCode "github.com/ginanjar-template-golang/shared-pkg/constants/http_code"
+	internalCode "github.com/ginanjar-template-golang/shared-pkg/constants/internal_code"
 	errHandler "github.com/ginanjar-template-golang/shared-pkg/errors"
 	"github.com/ginanjar-template-golang/shared-pkg/logger"
 	"github.com/ginanjar-template-golang/shared-pkg/translator"
@@ -14,9 +16,10 @@ import (
 
 // MetaData standard response meta
 type MetaData struct {
-	RequestID string `json:"request_id"`
-	HttpCode  int    `json:"http_code"`
-	Message   string `json:"message"`
+	RequestID    string `json:"request_id"`
+	HttpCode     int    `json:"http_code"`
+	InternalCode int    `json:"internal_code,omitempty"` // error responses only
+	Message      string `json:"message"`
 }
 
 // Standard response format
@@ -27,8 +30,17 @@ type Response struct {
 }
 
 type ResponseError struct {
-	Meta  MetaData `json:"meta"`
-	Error any      `json:"error,omitempty"`
+	Meta  MetaData           `json:"meta"`
+	Error any                `json:"error,omitempty"`
+	Stack []utils.StackFrame `json:"stack,omitempty"` // see errors.SetStackExposure
+}
+
+// debugStack returns the AppError stack when errors.SetStackExposure is on
+func debugStack(appErr errHandler.AppError) []utils.StackFrame {
+	if errHandler.StackExposed() {
+		return appErr.StackFrames()
+	}
+	return nil
 }
 
 // Pagination response format
@@ -38,10 +50,19 @@ type Pagination struct {
 	Limit    int `json:"limit"`
 	TotalRow int `json:"total_row"`
 	Results  any `json:"results,omitempty"`
+
+	// keyset pagination, see CursorPaginationResponse; omitted when unset so
+	// offset responses keep their original shape
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	HasMore    bool   `json:"has_more,omitempty"`
 }
 
 // helper untuk auto-generate request_id jika belum ada
 func getRequestID(c *gin.Context) string {
+	if rc := utils.GetRequestContext(c.Request.Context()); rc != nil && rc.RequestID != "" {
+		return rc.RequestID
+	}
 	reqID := c.GetString("request_id")
 	if reqID == "" {
 		reqID = utils.NewRequestID()
@@ -50,87 +71,93 @@ func getRequestID(c *gin.Context) string {
 	return reqID
 }
 
+// requestContext returns the request-scoped context with the resolved request_id
+func requestContext(c *gin.Context) (context.Context, string) {
+	ctx := c.Request.Context()
+	reqID := getRequestID(c)
+	if utils.GetRequestContext(ctx) == nil {
+		ctx = utils.WithRequestContext(ctx, &utils.RequestContext{RequestID: reqID})
+	}
+	return ctx, reqID
+}
+
 // ========================
 // SUCCESS RESPONSES
 // ========================
 func Success(c *gin.Context, messageKey string, data any) {
-	reqID := getRequestID(c)
+	ctx, reqID := requestContext(c)
 
-	logger.Info(translator.GetMessageByLang(messageKey), map[string]any{
-		"request_id": reqID,
-		"status":     httpCode.SuccessOK,
-		"method":     c.Request.Method,
-		"path":       c.FullPath(),
-		"results":    data,
+	logger.InfoCtx(ctx, translator.GetMessageByLang(messageKey), map[string]any{
+		"status":  httpCode.SuccessOK,
+		"method":  c.Request.Method,
+		"path":    c.FullPath(),
+		"results": data,
 	})
 
 	c.JSON(http.StatusOK, Response{
 		Meta: MetaData{
 			RequestID: reqID,
 			HttpCode:  httpCode.SuccessOK,
-			Message:   translator.GetMessageGlobal(messageKey),
+			Message:   translator.GetMessageCtx(ctx, messageKey),
 		},
 		Results: data,
 	})
 }
 
 func Created(c *gin.Context, messageKey string, data any) {
-	reqID := getRequestID(c)
+	ctx, reqID := requestContext(c)
 
-	logger.Info(translator.GetMessageByLang(messageKey), map[string]any{
-		"request_id": reqID,
-		"status":     httpCode.SuccessCreated,
-		"method":     c.Request.Method,
-		"path":       c.FullPath(),
-		"results":    data,
+	logger.InfoCtx(ctx, translator.GetMessageByLang(messageKey), map[string]any{
+		"status":  httpCode.SuccessCreated,
+		"method":  c.Request.Method,
+		"path":    c.FullPath(),
+		"results": data,
 	})
 
 	c.JSON(http.StatusCreated, Response{
 		Meta: MetaData{
 			RequestID: reqID,
 			HttpCode:  httpCode.SuccessCreated,
-			Message:   translator.GetMessageGlobal(messageKey),
+			Message:   translator.GetMessageCtx(ctx, messageKey),
 		},
 		Results: data,
 	})
 }
 
 func Updated(c *gin.Context, messageKey string, data any) {
-	reqID := getRequestID(c)
+	ctx, reqID := requestContext(c)
 
-	logger.Info(translator.GetMessageByLang(messageKey), map[string]any{
-		"request_id": reqID,
-		"status":     httpCode.SuccessOK,
-		"method":     c.Request.Method,
-		"path":       c.FullPath(),
-		"results":    data,
+	logger.InfoCtx(ctx, translator.GetMessageByLang(messageKey), map[string]any{
+		"status":  httpCode.SuccessOK,
+		"method":  c.Request.Method,
+		"path":    c.FullPath(),
+		"results": data,
 	})
 
 	c.JSON(http.StatusOK, Response{
 		Meta: MetaData{
 			RequestID: reqID,
 			HttpCode:  httpCode.SuccessOK,
-			Message:   translator.GetMessageGlobal(messageKey),
+			Message:   translator.GetMessageCtx(ctx, messageKey),
 		},
 		Results: data,
 	})
 }
 
 func Deleted(c *gin.Context, messageKey string) {
-	reqID := getRequestID(c)
+	ctx, reqID := requestContext(c)
 
-	logger.Info(translator.GetMessageByLang(messageKey), map[string]any{
-		"request_id": reqID,
-		"status":     httpCode.SuccessOK,
-		"method":     c.Request.Method,
-		"path":       c.FullPath(),
+	logger.InfoCtx(ctx, translator.GetMessageByLang(messageKey), map[string]any{
+		"status": httpCode.SuccessOK,
+		"method": c.Request.Method,
+		"path":   c.FullPath(),
 	})
 
 	c.JSON(http.StatusOK, Response{
 		Meta: MetaData{
 			RequestID: reqID,
 			HttpCode:  httpCode.SuccessOK,
-			Message:   translator.GetMessageGlobal(messageKey),
+			Message:   translator.GetMessageCtx(ctx, messageKey),
 		},
 	})
 }
@@ -139,30 +166,47 @@ func Deleted(c *gin.Context, messageKey string) {
 // PAGINATION RESPONSE
 // ========================
 func PaginationResponse(c *gin.Context, messageKey string, data Pagination) {
-	reqID := getRequestID(c)
+	paginationResponse(c, messageKey, data, map[string]any{
+		"page":  data.Page,
+		"limit": data.Limit,
+		"total": data.TotalRow,
+	})
+}
 
-	logger.Info(translator.GetMessageByLang(messageKey), map[string]any{
-		"request_id": reqID,
-		"page":       data.Page,
-		"limit":      data.Limit,
-		"total":      data.TotalRow,
-		"status":     httpCode.SuccessOK,
-		"method":     c.Request.Method,
-		"path":       c.FullPath(),
-		"results":    data.Results,
+// CursorPaginationResponse sends a keyset page: page and total_row stay 0,
+// clients follow next_cursor / prev_cursor while has_more is true.
+func CursorPaginationResponse(c *gin.Context, messageKey string, data Pagination) {
+	paginationResponse(c, messageKey, data, map[string]any{
+		"limit":       data.Limit,
+		"has_more":    data.HasMore,
+		"next_cursor": data.NextCursor != "",
+		"prev_cursor": data.PrevCursor != "",
 	})
+}
+
+func paginationResponse(c *gin.Context, messageKey string, data Pagination, fields map[string]any) {
+	ctx, reqID := requestContext(c)
+
+	fields["status"] = httpCode.SuccessOK
+	fields["method"] = c.Request.Method
+	fields["path"] = c.FullPath()
+	fields["results"] = data.Results
+	logger.InfoCtx(ctx, translator.GetMessageByLang(messageKey), fields)
 
 	c.JSON(http.StatusOK, Response{
 		Meta: MetaData{
 			RequestID: reqID,
 			HttpCode:  httpCode.SuccessOK,
-			Message:   translator.GetMessageGlobal(messageKey),
+			Message:   translator.GetMessageCtx(ctx, messageKey),
 		},
 		Pagination: Pagination{
-			Page:     data.Page,
-			Size:     data.Size,
-			Limit:    data.Limit,
-			TotalRow: data.TotalRow,
+			Page:       data.Page,
+			Size:       data.Size,
+			Limit:      data.Limit,
+			TotalRow:   data.TotalRow,
+			NextCursor: data.NextCursor,
+			PrevCursor: data.PrevCursor,
+			HasMore:    data.HasMore,
 		},
 		Results: data.Results,
 	})
@@ -172,16 +216,24 @@ func PaginationResponse(c *gin.Context, messageKey string, data Pagination) {
 // ERROR RESPONSE
 // ========================
 func FromAppError(c *gin.Context, err error) {
-	reqID := getRequestID(c)
+	ctx, reqID := requestContext(c)
+
+	if useProblemFormat(c) {
+		problemFromError(c, ctx, reqID, err)
+		return
+	}
 
-	if internalErr, ok := err.(errHandler.AppError); ok {
+	// Cause is never serialized, only Data is sent to the client
+	if internalErr, ok := errHandler.FromError(err); ok {
 		c.JSON(internalErr.HttpCode, ResponseError{
 			Meta: MetaData{
-				RequestID: reqID,
-				HttpCode:  internalErr.HttpCode,
-				Message:   translator.GetMessageGlobal(internalErr.MessageKey),
+				RequestID:    reqID,
+				HttpCode:     internalErr.HttpCode,
+				InternalCode: internalErr.InternalCode,
+				Message:      translator.GetMessageCtx(ctx, internalErr.MessageKey),
 			},
 			Error: internalErr.Data,
+			Stack: debugStack(internalErr),
 		})
 		return
 	}
@@ -189,9 +241,10 @@ func FromAppError(c *gin.Context, err error) {
 	// fallback
 	c.JSON(http.StatusInternalServerError, ResponseError{
 		Meta: MetaData{
-			RequestID: reqID,
-			HttpCode:  httpCode.InternalServerError,
-			Message:   "Unexpected error",
+			RequestID:    reqID,
+			HttpCode:     httpCode.InternalServerError,
+			InternalCode: internalCode.UnknownError,
+			Message:      "Unexpected error",
 		},
 		Error: err.Error(),
 	})
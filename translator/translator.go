@@ -1,11 +1,14 @@
 package translator
 
 import (
+	"context"
 	_ "embed"
 	"encoding/json"
 	"fmt"
-	"strings"
 	"sync"
+
+	"github.com/ginanjar-template-golang/shared-pkg/utils"
+	"golang.org/x/text/language"
 )
 
 type Translator struct {
@@ -16,6 +19,13 @@ var (
 	// Global default translator (English)
 	globalTranslator *Translator
 	mu               sync.RWMutex
+
+	// Per-language translators keyed by supported base language, parsed once and reused
+	langTranslators sync.Map
+
+	// Supported languages, the first is the fallback
+	supportedLangs = []language.Tag{language.English, language.Indonesian}
+	langMatcher    = language.NewMatcher(supportedLangs)
 )
 
 // ================== Embed JSON files ==================
@@ -62,14 +72,39 @@ func newTranslatorFromBytes(bytesList ...[]byte) *Translator {
 }
 
 func newTranslator(lang string) *Translator {
-	switch strings.ToLower(lang) {
-	case "id", "id-id":
+	switch baseLang(lang) {
+	case "id":
 		return newTranslatorFromBytes(idErrorsJSON, idSuccessJSON, idValidationJSON)
 	default:
 		return newTranslatorFromBytes(enErrorsJSON, enSuccessJSON, enValidationJSON)
 	}
 }
 
+// baseLang reduces a language tag or Accept-Language header, e.g.
+// "id-ID,id;q=0.9,en;q=0.8", to the best supported base language ("en", "id").
+func baseLang(lang string) string {
+	tags, _, err := language.ParseAcceptLanguage(lang)
+	if err != nil || len(tags) == 0 {
+		return supportedLangs[0].String()
+	}
+	_, index, _ := langMatcher.Match(tags...)
+	base, _ := supportedLangs[index].Base()
+	return base.String()
+}
+
+// getTranslator returns the cached translator of lang's base language, so the
+// cache never holds more entries than there are supported languages.
+func getTranslator(lang string) *Translator {
+	key := baseLang(lang)
+	if t, ok := langTranslators.Load(key); ok {
+		return t.(*Translator)
+	}
+	t, _ := langTranslators.LoadOrStore(key, newTranslator(key))
+	return t.(*Translator)
+}
+
+// InitGlobalTranslator sets the process-wide default language.
+// Per-request languages must be carried in context, see GetMessageCtx.
 func InitGlobalTranslator(lang string) {
 	t := newTranslator(lang)
 	mu.Lock()
@@ -107,9 +142,18 @@ func GetMessageByLang(key string, lang ...string) string {
 	if len(lang) > 0 && lang[0] != "" {
 		selectedLang = lang[0]
 	}
-	t := newTranslator(selectedLang)
+	t := getTranslator(selectedLang)
 	if msg, ok := t.messages[key]; ok {
 		return msg
 	}
 	return key
 }
+
+// GetMessageCtx translates key using the language carried in ctx,
+// falling back to the global translator when ctx has no language.
+func GetMessageCtx(ctx context.Context, key string) string {
+	if lang := utils.LangFromContext(ctx); lang != "" {
+		return GetMessageByLang(key, lang)
+	}
+	return GetMessageGlobal(key)
+}
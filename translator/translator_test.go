@@ -0,0 +1,48 @@
+package translator
+
+import "testing"
+
+func TestBaseLang(t *testing.T) {
+	tests := []struct {
+		lang string
+		want string
+	}{
+		{"en", "en"},
+		{"id", "id"},
+		{"ID-id", "id"},
+		{"id-ID,id;q=0.9", "id"},
+		{"id-ID,id;q=0.9,en-US;q=0.8,en;q=0.7", "id"},
+		{"en-US,en;q=0.9,id;q=0.8", "en"},
+		{"fr-FR,id;q=0.5", "id"},
+		{"fr", "en"},
+		{"", "en"},
+		{"not a language !!", "en"},
+	}
+	for _, tt := range tests {
+		if got := baseLang(tt.lang); got != tt.want {
+			t.Errorf("baseLang(%q) = %q, want %q", tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestGetTranslatorCachesSupportedLanguagesOnly(t *testing.T) {
+	for _, lang := range []string{"en", "en-US", "en-GB,en;q=0.9", "id", "id-ID,id;q=0.9", "xx-1", "zz", "fr-FR"} {
+		getTranslator(lang)
+	}
+
+	entries := 0
+	langTranslators.Range(func(key, _ any) bool {
+		if key != "en" && key != "id" {
+			t.Errorf("unexpected cache key %q", key)
+		}
+		entries++
+		return true
+	})
+	if entries > 2 {
+		t.Fatalf("cache holds %d translators, want at most 2", entries)
+	}
+
+	if GetMessageByLang("forbidden", "id-ID,id;q=0.9") == GetMessageByLang("forbidden", "en") {
+		t.Fatal("id-ID,id;q=0.9 should not fall back to English")
+	}
+}
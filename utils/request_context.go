@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RequestContext holds request-scoped values shared by middleware, interceptor,
+// logger, errors and response packages.
+type RequestContext struct {
+	RequestID  string
+	Lang       string
+	UserClaims map[string]any
+	TenantID   string
+	StartTime  time.Time
+}
+
+type requestContextKey struct{}
+
+// WithRequestContext returns a copy of ctx carrying rc.
+func WithRequestContext(ctx context.Context, rc *RequestContext) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, requestContextKey{}, rc)
+}
+
+// GetRequestContext returns the RequestContext stored in ctx, or nil if none.
+func GetRequestContext(ctx context.Context) *RequestContext {
+	if ctx == nil {
+		return nil
+	}
+	rc, _ := ctx.Value(requestContextKey{}).(*RequestContext)
+	return rc
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, or "" if none.
+func RequestIDFromContext(ctx context.Context) string {
+	if rc := GetRequestContext(ctx); rc != nil {
+		return rc.RequestID
+	}
+	return ""
+}
+
+// LangFromContext returns the language stored in ctx, or "" if none.
+func LangFromContext(ctx context.Context) string {
+	if rc := GetRequestContext(ctx); rc != nil {
+		return rc.Lang
+	}
+	return ""
+}
+
+// UserClaimsFromContext returns the authenticated user claims stored in ctx.
+func UserClaimsFromContext(ctx context.Context) map[string]any {
+	if rc := GetRequestContext(ctx); rc != nil {
+		return rc.UserClaims
+	}
+	return nil
+}
+
+// WithUserClaims returns a copy of ctx whose RequestContext carries claims.
+// The existing RequestContext is copied, never mutated.
+func WithUserClaims(ctx context.Context, claims map[string]any) context.Context {
+	rc := RequestContext{StartTime: time.Now()}
+	if existing := GetRequestContext(ctx); existing != nil {
+		rc = *existing
+	}
+	rc.UserClaims = claims
+	return WithRequestContext(ctx, &rc)
+}
+
+// TenantIDFromContext returns the tenant ID stored in ctx, or "" if none.
+func TenantIDFromContext(ctx context.Context) string {
+	if rc := GetRequestContext(ctx); rc != nil {
+		return rc.TenantID
+	}
+	return ""
+}
+
+// WithTenantID returns a copy of ctx whose RequestContext carries tenantID.
+// The existing RequestContext is copied, never mutated.
+func WithTenantID(ctx context.Context, tenantID string) context.Context {
+	rc := RequestContext{StartTime: time.Now()}
+	if existing := GetRequestContext(ctx); existing != nil {
+		rc = *existing
+	}
+	rc.TenantID = tenantID
+	return WithRequestContext(ctx, &rc)
+}
+
+// TenantIDFromClaims returns claims[claim] as a string, or "" if missing.
+func TenantIDFromClaims(claims map[string]any, claim string) string {
+	value, ok := claims[claim]
+	if !ok || value == nil {
+		return ""
+	}
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64: // JSON numbers
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRequestIDFromContext(t *testing.T) {
+	if got := RequestIDFromContext(context.Background()); got != "" {
+		t.Fatalf("RequestIDFromContext(no request) = %q, want empty", got)
+	}
+
+	ctx := WithRequestContext(context.Background(), &RequestContext{RequestID: "req-1"})
+	if got := RequestIDFromContext(ctx); got != "req-1" {
+		t.Fatalf("RequestIDFromContext = %q, want req-1", got)
+	}
+}
@@ -11,12 +11,16 @@ var (
 	mu        sync.RWMutex
 )
 
+// Deprecated: the value is shared by every request in the process.
+// Use WithRequestContext instead.
 func SetRequestID(id string) {
 	mu.Lock()
 	defer mu.Unlock()
 	requestID = id
 }
 
+// Deprecated: the value is shared by every request in the process.
+// Use RequestIDFromContext instead.
 func GetRequestID() string {
 	mu.RLock()
 	id := requestID
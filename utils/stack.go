@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"runtime"
+	"strings"
+)
+
+const maxStackFrames = 32
+
+// StackFrame is one entry of a captured call stack.
+type StackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// CaptureStack returns the call stack of its caller, skipping skip extra frames
+// and every runtime frame (so it also works inside a deferred recover).
+func CaptureStack(skip int) []StackFrame {
+	pcs := make([]uintptr, maxStackFrames+skip+8)
+	n := runtime.Callers(skip+2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	out := make([]StackFrame, 0, n)
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "runtime.") {
+			out = append(out, StackFrame{Function: frame.Function, File: frame.File, Line: frame.Line})
+			if len(out) == maxStackFrames {
+				break
+			}
+		}
+		if !more {
+			break
+		}
+	}
+	return out
+}
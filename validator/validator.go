@@ -1,16 +1,12 @@
 package validator
 
 import (
-	"encoding/json"
+	"context"
 	"reflect"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	grpcCode "github.com/ginanjar-template-golang/shared-pkg/constants/grpc_code"
-	httpCode "github.com/ginanjar-template-golang/shared-pkg/constants/http_code"
-	internalCode "github.com/ginanjar-template-golang/shared-pkg/constants/internal_code"
 	appError "github.com/ginanjar-template-golang/shared-pkg/errors"
-	"github.com/ginanjar-template-golang/shared-pkg/logger"
 	"github.com/ginanjar-template-golang/shared-pkg/translator"
 	"github.com/go-playground/validator/v10"
 	"golang.org/x/text/cases"
@@ -39,26 +35,29 @@ func GetValidator() *validator.Validate {
 }
 
 func ValidateRequest(c *gin.Context, data any) *appError.AppError {
+	ctx := c.Request.Context()
 	if err := c.ShouldBindJSON(data); err != nil {
-		msg := translator.GetMessageGlobal("invalidRequest")
-		logger.LogMapLevel("warn", internalCode.InvalidRequestData, msg, err.Error())
-
-		return &appError.AppError{
-			HttpCode:   httpCode.BadRequest,
-			GrpcCode:   grpcCode.InvalidArgument,
-			MessageKey: "invalidRequest",
-			Data:       err.Error(),
-		}
+		appErr := appError.NewCtx(ctx, "invalidRequest", "", err.Error())
+		return &appErr
 	}
 
-	return ValidateStruct(data)
+	return ValidateStructCtx(ctx, data)
 }
 
 func ValidateGrpcRequest(data any) *appError.AppError {
-	return ValidateStruct(data)
+	return ValidateStructCtx(context.Background(), data)
+}
+
+func ValidateGrpcRequestCtx(ctx context.Context, data any) *appError.AppError {
+	return ValidateStructCtx(ctx, data)
 }
 
 func ValidateStruct(data any) *appError.AppError {
+	return ValidateStructCtx(context.Background(), data)
+}
+
+// ValidateStructCtx validates data and translates messages using the language carried in ctx.
+func ValidateStructCtx(ctx context.Context, data any) *appError.AppError {
 	v := GetValidator()
 	if err := v.Struct(data); err != nil {
 		errMap := make(map[string]string)
@@ -66,26 +65,20 @@ func ValidateStruct(data any) *appError.AppError {
 			field := e.Field()
 			tag := e.Tag()
 			param := e.Param()
-			errMap[field] = buildMessage(field, tag, param)
+			errMap[field] = FieldMessage(ctx, field, tag, param)
 		}
 
-		jsonMsg, _ := json.Marshal(errMap)
-		logger.LogMapLevel("info", internalCode.ValidationError, translator.GetMessageGlobal("validationFailed"), jsonMsg)
-
-		return &appError.AppError{
-			HttpCode:   httpCode.BadRequest,
-			GrpcCode:   grpcCode.InvalidArgument,
-			MessageKey: "validationFailed",
-			Data:       errMap,
-		}
+		appErr := appError.NewCtx(ctx, "validationFailed", "", errMap)
+		return &appErr
 	}
 
 	return nil
 }
 
-// buildMessage constructs a user-friendly validation message.
-func buildMessage(field, tag, param string) string {
-	template := translator.GetMessageGlobal(tag)
+// FieldMessage translates the validation message tag for field, e.g.
+// FieldMessage(ctx, "limit", "max", "50"), as used in validationFailed data.
+func FieldMessage(ctx context.Context, field, tag, param string) string {
+	template := translator.GetMessageCtx(ctx, tag)
 	c := cases.Title(language.Und)
 	fieldName := c.String(field)
 